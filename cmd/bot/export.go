@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/bot"
+	"github.com/plastinin/photo-quiz-bot/internal/config"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+const exportUsage = `Usage:
+  bot export [FILE]       выгрузить все ситуации с фото в ZIP-архив
+                          (по умолчанию photo-quiz-export-ГГГГММДД.zip)`
+
+func runExport(args []string) {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, exportUsage)
+		os.Exit(2)
+	}
+
+	file := fmt.Sprintf("photo-quiz-export-%s.zip", time.Now().Format("20060102"))
+	if len(args) == 1 {
+		file = args[0]
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+
+	repos, err := openRepositories(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer repos.Close()
+
+	// Фото, которых нет в хранилище, скачиваются через Bot API
+	botAPI, err := tgbotapi.NewBotAPI(cfg.BotToken)
+	if err != nil {
+		log.Fatalf("Failed to create bot API: %v", err)
+	}
+
+	photoService := service.NewPhotoService(repos.Situations, repos.Photos, bot.NewFileDownloader(botAPI))
+	exportService := service.NewExportService(repos.Situations, repos.Decks, photoService)
+
+	f, err := os.Create(file)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", file, err)
+	}
+
+	result, err := exportService.Export(ctx, f)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		os.Remove(file)
+		log.Fatalf("Export failed: %v", err)
+	}
+
+	for _, err := range result.Failed {
+		log.Printf("Skipped photo: %v", err)
+	}
+	log.Printf("Exported %d situations and %d photos to %s", result.Situations, result.Photos, file)
+}
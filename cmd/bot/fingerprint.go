@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/bot"
+	"github.com/plastinin/photo-quiz-bot/internal/config"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+const fingerprintUsage = `Usage:
+  bot fingerprint         посчитать хеши фото, добавленных до поиска дублей по фото`
+
+func runFingerprint(args []string) {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, fingerprintUsage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+
+	repos, err := openRepositories(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer repos.Close()
+
+	// Фото, которых нет в хранилище, скачиваются через Bot API
+	botAPI, err := tgbotapi.NewBotAPI(cfg.BotToken)
+	if err != nil {
+		log.Fatalf("Failed to create bot API: %v", err)
+	}
+
+	photoService := service.NewPhotoService(repos.Situations, repos.Photos, bot.NewFileDownloader(botAPI))
+
+	result, err := photoService.FingerprintStored(ctx)
+	for _, err := range result.Failed {
+		log.Printf("Skipped photo: %v", err)
+	}
+	if err != nil {
+		log.Fatalf("Fingerprint failed: %v", err)
+	}
+	log.Printf("Hashed %d photos, %d without a supported image format", result.Hashed, result.Skipped)
+}
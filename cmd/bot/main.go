@@ -2,19 +2,34 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/plastinin/photo-quiz-bot/internal/bot"
 	"github.com/plastinin/photo-quiz-bot/internal/config"
-	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
 	"github.com/plastinin/photo-quiz-bot/internal/service"
 	"github.com/plastinin/photo-quiz-bot/internal/web"
 )
 
 func main() {
+	// Подкоманды: bot migrate ..., bot export ..., bot fingerprint
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fingerprint" {
+		runFingerprint(os.Args[2:])
+		return
+	}
+
 	// Загружаем конфигурацию
 	cfg, err := config.Load()
 	if err != nil {
@@ -25,26 +40,71 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Подключаемся к базе данных
-	db, err := postgres.New(ctx, cfg.DB.DSN())
+	// Подключаемся к хранилищу и создаём репозитории
+	repos, err := openRepositories(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer repos.Close()
+
+	// Роли пользователей; владелец бота задаётся ADMIN_ID
+	userService := service.NewUserService(repos.Users, cfg.AdminID)
+	if err := userService.EnsureOwner(ctx); err != nil {
+		log.Fatalf("Failed to save bot owner: %v", err)
+	}
+
+	// Создаём игровой движок; догадки сравниваются с ответом с порогом ANSWER_MATCH_THRESHOLD
+	gameService := service.NewGameService(repos.Situations, repos.Sessions, service.NewAnswerMatcher(cfg.AnswerMatchThreshold))
+	if err := gameService.SetDefaultTimer(cfg.PhotoTimer); err != nil {
+		log.Fatalf("Invalid PHOTO_TIMER: %v", err)
+	}
+
+	// Восстанавливаем незавершённые веб-комнаты
+	if err := gameService.LoadSessions(ctx); err != nil {
+		log.Fatalf("Failed to load game sessions: %v", err)
+	}
+
+	// Подключаемся к Telegram Bot API
+	botAPI, err := tgbotapi.NewBotAPI(cfg.BotToken)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to create bot API: %v", err)
 	}
-	defer db.Close()
-	log.Println("Connected to database")
 
-	// Создаём репозиторий и сервис
-	repo := postgres.NewSituationRepository(db)
-	gameService := service.NewGameService(repo)
+	// Фото скачиваются из Telegram один раз и дальше отдаются из хранилища
+	photoService := service.NewPhotoService(repos.Situations, repos.Photos, bot.NewFileDownloader(botAPI))
+
+	// Поиск уже добавленных ситуаций с тем же ответом или фото
+	duplicateService := service.NewDuplicateService(repos.Situations)
+
+	// Импорт и резервная копия библиотеки в ZIP-архивах
+	importService := service.NewImportService(repos.Situations, repos.Decks, photoService, duplicateService)
+	exportService := service.NewExportService(repos.Situations, repos.Decks, photoService)
 
-	// Создаём веб-сервер
-	webServer, err := web.NewServer(":"+cfg.WebPort, repo, cfg.BotToken)
+	// Создаём веб-сервер (использует тот же игровой движок, что и бот)
+	secret := photoURLSecret(cfg.PhotoURLSecret)
+	signer := web.NewURLSigner(secret, cfg.PhotoURLTTL)
+
+	// Админ-панель; фото из браузера загружаются в Telegram через чат администратора
+	adminAuth := web.NewAdminAuth(secret, cfg.WebURL, userService)
+	adminHandlers := web.NewAdminHandlers(gameService, repos.Situations, repos.Decks, photoService, userService, adminAuth,
+		func(chatID int64) service.PhotoUploader { return bot.NewPhotoUploader(botAPI, chatID) })
+
+	webServer, err := web.NewServer(":"+cfg.WebPort, gameService, repos.Situations, repos.Decks, photoService, signer, adminHandlers)
 	if err != nil {
 		log.Fatalf("Failed to create web server: %v", err)
 	}
 
-	// Создаём и запускаем Telegram бота (передаём webServer для связи)
-	telegramBot, err := bot.New(cfg.BotToken, gameService, repo, cfg.AdminID, webServer)
+	// Незавершённый ввод в боте переживает перезапуск, брошенный — удаляется через DRAFT_TTL
+	draftService := service.NewDraftService(repos.Drafts, cfg.DraftTTL)
+
+	// Ситуации, предложенные игроками, попадают в библиотеку после одобрения администратором
+	submissionService := service.NewSubmissionService(repos.Submissions, repos.Situations, photoService)
+
+	// В групповых чатах ответы игроков текстом проверяются автоматически
+	guessService := service.NewGuessService(gameService, repos.ChatScores)
+
+	// Создаём и запускаем Telegram бота
+	telegramBot, err := bot.New(botAPI, gameService, repos.Situations, repos.Decks, photoService, importService, exportService, userService, draftService, duplicateService, submissionService, guessService, adminAuth)
 	if err != nil {
 		log.Fatalf("Failed to create bot: %v", err)
 	}
@@ -72,4 +132,19 @@ func main() {
 	}
 
 	log.Println("Application stopped")
+}
+
+// photoURLSecret возвращает ключ подписи ссылок на фото; без PHOTO_URL_SECRET ссылки
+// перестают действовать после перезапуска
+func photoURLSecret(secret string) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("Failed to generate photo URL secret: %v", err)
+	}
+	log.Println("PHOTO_URL_SECRET is not set, using a random key")
+	return key
 }
\ No newline at end of file
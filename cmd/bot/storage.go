@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/plastinin/photo-quiz-bot/internal/config"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/repository/memory"
+	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
+	"github.com/plastinin/photo-quiz-bot/internal/storage"
+)
+
+type repositories struct {
+	Situations  domain.SituationRepository
+	Sessions    domain.SessionRepository
+	Decks       domain.DeckRepository
+	Users       domain.UserRepository
+	Drafts      domain.DraftRepository
+	Submissions domain.SubmissionRepository
+	ChatScores  domain.ChatScoreRepository
+	Photos      storage.BlobStore
+
+	close func()
+}
+
+func (r *repositories) Close() {
+	if r.close != nil {
+		r.close()
+	}
+}
+
+// openRepositories создаёт репозитории выбранного хранилища (STORAGE=postgres|memory)
+func openRepositories(ctx context.Context, cfg *config.Config) (*repositories, error) {
+	if cfg.Storage == "memory" {
+		log.Println("Using in-memory storage: data will be lost on restart")
+		return &repositories{
+			Situations:  memory.NewSituationRepository(),
+			Sessions:    memory.NewSessionRepository(),
+			Decks:       memory.NewDeckRepository(),
+			Users:       memory.NewUserRepository(),
+			Drafts:      memory.NewDraftRepository(),
+			Submissions: memory.NewSubmissionRepository(),
+			ChatScores:  memory.NewChatScoreRepository(),
+			Photos:      storage.NewMemoryStore(),
+		}, nil
+	}
+
+	photos, err := storage.NewFSStore(cfg.PhotoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := postgres.New(ctx, cfg.DB.DSN())
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Connected to database")
+
+	// Применяем миграции схемы
+	if cfg.DB.AutoMigrate {
+		if err := migrateUp(ctx, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return &repositories{
+		Situations:  postgres.NewSituationRepository(db),
+		Sessions:    postgres.NewSessionRepository(db),
+		Decks:       postgres.NewDeckRepository(db),
+		Users:       postgres.NewUserRepository(db),
+		Drafts:      postgres.NewDraftRepository(db),
+		Submissions: postgres.NewSubmissionRepository(db),
+		ChatScores:  postgres.NewChatScoreRepository(db),
+		Photos:      photos,
+		close:       db.Close,
+	}, nil
+}
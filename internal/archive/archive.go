@@ -0,0 +1,289 @@
+// Package archive — формат ZIP-архива с ситуациями: манифест (manifest.json или manifest.csv)
+// и папки с фотографиями. Используется для массового импорта и для резервной копии библиотеки.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ManifestJSON = "manifest.json"
+	ManifestCSV  = "manifest.csv"
+
+	// Version — версия формата, которую пишет экспорт
+	Version = 1
+
+	// MaxPhotoSize — максимальный размер одного фото в архиве
+	MaxPhotoSize = 10 << 20
+	// maxManifestSize — максимальный размер манифеста
+	maxManifestSize = 1 << 20
+)
+
+var ErrNoManifest = errors.New("в архиве нет manifest.json или manifest.csv")
+
+// Manifest — описание ситуаций архива
+type Manifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at,omitzero"`
+	Decks      []Deck    `json:"decks,omitempty"`
+	Situations []Item    `json:"situations"`
+}
+
+// Deck — колода архива; при импорте недостающие колоды создаются
+type Deck struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Item — одна ситуация манифеста. Фото задаются списком Photos или папкой Folder
+// (тогда берутся все изображения папки по алфавиту).
+type Item struct {
+	Answer     string   `json:"answer"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Hints      []string `json:"hints,omitempty"`
+	Difficulty int      `json:"difficulty,omitempty"`
+	Decks      []string `json:"decks,omitempty"`
+	Used       bool     `json:"used,omitempty"`
+	Folder     string   `json:"folder,omitempty"`
+	Photos     []string `json:"photos,omitempty"`
+
+	// SortOrders — порядок показа фото из Photos; без него фото идут в порядке списка
+	SortOrders []int `json:"sort_orders,omitempty"`
+
+	// Метаданные резервной копии, при импорте не используются
+	ID        int       `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+
+	// Err — ошибка разбора строки CSV; такая ситуация не импортируется
+	Err error `json:"-"`
+}
+
+// Archive — открытый ZIP-архив с ситуациями
+type Archive struct {
+	Manifest Manifest
+
+	files map[string]*zip.File
+	root  string // папка, в которой лежит манифест
+}
+
+// Open читает архив и его манифест
+func Open(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть ZIP: %w", err)
+	}
+
+	a := &Archive{files: make(map[string]*zip.File)}
+	var manifest *zip.File
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		a.files[name] = f
+
+		// Манифест может лежать в корне или в единственной папке верхнего уровня
+		base := path.Base(name)
+		if (base == ManifestJSON || base == ManifestCSV) && strings.Count(name, "/") <= 1 {
+			if manifest == nil || strings.Count(name, "/") < strings.Count(manifest.Name, "/") {
+				manifest = f
+				a.root = path.Dir(name)
+			}
+		}
+	}
+	if manifest == nil {
+		return nil, ErrNoManifest
+	}
+
+	data, err := readFile(manifest, maxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать манифест: %w", err)
+	}
+
+	if path.Base(manifest.Name) == ManifestJSON {
+		if err := json.Unmarshal(data, &a.Manifest); err != nil {
+			return nil, fmt.Errorf("некорректный manifest.json: %w", err)
+		}
+	} else {
+		items, err := parseCSV(data)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный manifest.csv: %w", err)
+		}
+		a.Manifest.Situations = items
+	}
+
+	return a, nil
+}
+
+// Photos возвращает пути фото ситуации внутри архива
+func (a *Archive) Photos(item Item) ([]string, error) {
+	if len(item.Photos) > 0 {
+		names := make([]string, len(item.Photos))
+		for i, p := range item.Photos {
+			name := a.resolve(p)
+			if _, ok := a.files[name]; !ok {
+				return nil, fmt.Errorf("нет файла %s", p)
+			}
+			names[i] = name
+		}
+
+		if len(item.SortOrders) == len(names) {
+			order := make([]int, len(names))
+			for i := range order {
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool {
+				return item.SortOrders[order[i]] < item.SortOrders[order[j]]
+			})
+			sorted := make([]string, len(names))
+			for i, j := range order {
+				sorted[i] = names[j]
+			}
+			names = sorted
+		}
+		return names, nil
+	}
+
+	if item.Folder == "" {
+		return nil, errors.New("не указаны фото (photos) или папка (folder)")
+	}
+
+	dir := a.resolve(item.Folder)
+	var names []string
+	for name := range a.files {
+		if path.Dir(name) == dir && isImageName(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("в папке %s нет изображений", item.Folder)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ReadFile читает файл архива не больше MaxPhotoSize байт
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, fmt.Errorf("нет файла %s", name)
+	}
+	return readFile(f, MaxPhotoSize)
+}
+
+func (a *Archive) resolve(name string) string {
+	return path.Clean(path.Join(a.root, strings.ReplaceAll(name, "\\", "/")))
+}
+
+// readFile распаковывает файл, не доверяя заявленному размеру (защита от ZIP-бомб)
+func readFile(f *zip.File, limit int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("файл %s больше %d МБ", f.Name, limit>>20)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("файл %s больше %d МБ", f.Name, limit>>20)
+	}
+	return data, nil
+}
+
+func isImageName(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".webp":
+		return true
+	}
+	return false
+}
+
+// parseCSV разбирает манифест с заголовком: answer (обязательно), aliases, hints, folder, photos, difficulty, decks.
+// Разделитель — запятая или точка с запятой; несколько синонимов, подсказок, фото или колод в ячейке разделяются «|».
+func parseCSV(data []byte) ([]Item, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff") // BOM, который добавляет Excel
+
+	r := csv.NewReader(strings.NewReader(text))
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["answer"]; !ok {
+		return nil, errors.New("нет колонки answer")
+	}
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var items []Item
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		item := Item{
+			Answer:  cell(record, "answer"),
+			Aliases: splitList(cell(record, "aliases")),
+			Hints:   splitList(cell(record, "hints")),
+			Folder:  cell(record, "folder"),
+			Photos:  splitList(cell(record, "photos")),
+			Decks:   splitList(cell(record, "decks")),
+		}
+		if d := cell(record, "difficulty"); d != "" {
+			item.Difficulty, err = strconv.Atoi(d)
+			if err != nil {
+				item.Err = fmt.Errorf("некорректная сложность %q", d)
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Writer пишет архив в формате, который понимает Open: сначала файлы фото, в конце манифест
+type Writer struct {
+	zw *zip.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// AddFile добавляет файл; name — путь внутри архива через «/».
+// Фото уже сжаты, поэтому кладутся без компрессии.
+func (w *Writer) AddFile(name string, data []byte) error {
+	f, err := w.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: time.Now()})
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close записывает manifest.json и завершает архив
+func (w *Writer) Close(manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	f, err := w.zw.Create(ManifestJSON)
+	if err != nil {
+		return fmt.Errorf("add manifest: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	return w.zw.Close()
+}
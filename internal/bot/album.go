@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// albumWait — сколько ждать следующего фото альбома: Telegram присылает фото альбома
+// отдельными сообщениями с общим MediaGroupID, и признака последнего среди них нет
+const albumWait = 1500 * time.Millisecond
+
+// pendingAlbum — альбом, который присылают в /add: считаем фото и подтверждаем их одним сообщением
+type pendingAlbum struct {
+	groupID string
+	first   int // номер первого добавленного фото в черновике
+	added   int
+	skipped int
+	timer   *time.Timer
+
+	duplicates []service.Duplicate
+}
+
+func (a *pendingAlbum) stop() {
+	if a != nil {
+		a.timer.Stop()
+	}
+}
+
+// addAlbumPhoto добавляет в черновик фото из альбома. Подтверждение уходит, когда
+// фото альбома перестают приходить; лимит фото действует на весь альбом.
+// checkDuplicates — искать ли в библиотеке такое же фото.
+func (h *Handler) addAlbumPhoto(ctx context.Context, userID, chatID int64, groupID string, photo tgbotapi.PhotoSize, checkDuplicates bool) {
+	// Дубли ищем до блокировки: для хеша фото скачивается из Telegram
+	var duplicates []service.Duplicate
+	if checkDuplicates {
+		duplicates = h.photoDuplicates(ctx, photo, 0)
+	}
+
+	var flush *pendingAlbum
+	var flushTotal int
+
+	h.addStateMu.Lock()
+	state, ok := h.addState[userID]
+	if !ok {
+		h.addStateMu.Unlock()
+		return
+	}
+
+	album := state.album
+	if album == nil || album.groupID != groupID {
+		// Начался новый альбом — про предыдущий отчитываемся сразу
+		if album != nil {
+			album.timer.Stop()
+			flush, flushTotal = album, len(state.Photos)
+		}
+		album = &pendingAlbum{groupID: groupID, first: len(state.Photos) + 1}
+		album.timer = time.AfterFunc(albumWait, func() { h.flushAlbum(userID, chatID, album) })
+		state.album = album
+	} else {
+		album.timer.Reset(albumWait)
+	}
+
+	if len(state.Photos) < domain.MaxPhotosPerSituation {
+		state.addPhoto(photo)
+		album.added++
+		for _, d := range duplicates {
+			d.Photo = len(state.Photos)
+			album.duplicates = append(album.duplicates, d)
+		}
+	} else {
+		album.skipped++
+	}
+	h.addStateMu.Unlock()
+	h.saveAddDraft(ctx, userID)
+
+	if flush != nil {
+		h.sendAlbumReport(chatID, flush, flushTotal)
+	}
+}
+
+// flushAlbum отправляет подтверждение по альбому, если он всё ещё последний в черновике
+func (h *Handler) flushAlbum(userID, chatID int64, album *pendingAlbum) {
+	h.addStateMu.Lock()
+	state, ok := h.addState[userID]
+	if !ok || state.album != album {
+		h.addStateMu.Unlock()
+		return
+	}
+	state.album = nil
+	report := *album
+	total := len(state.Photos)
+	h.addStateMu.Unlock()
+
+	h.sendAlbumReport(chatID, &report, total)
+}
+
+func (h *Handler) sendAlbumReport(chatID int64, album *pendingAlbum, total int) {
+	var sb strings.Builder
+	switch album.added {
+	case 0:
+		sb.WriteString("📷 Фото из альбома не добавлены")
+	case 1:
+		sb.WriteString(fmt.Sprintf("📷 Из альбома добавлено фото %d", album.first))
+	default:
+		sb.WriteString(fmt.Sprintf("📷 Из альбома добавлено %d фото: %d–%d", album.added, album.first, album.first+album.added-1))
+	}
+	sb.WriteString(fmt.Sprintf(" (всего %d из %d)", total, domain.MaxPhotosPerSituation))
+	if warning := duplicateWarning(album.duplicates); warning != "" {
+		sb.WriteString("\n\n" + warning)
+	}
+	if album.skipped > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n⚠️ Не поместилось фото: %d — в ситуации не больше %d фотографий", album.skipped, domain.MaxPhotosPerSituation))
+	}
+
+	if total >= domain.MaxPhotosPerSituation {
+		sb.WriteString("\n\nНажмите кнопку для завершения")
+	} else {
+		sb.WriteString("\n\nМожете отправить ещё или нажмите кнопку для завершения")
+	}
+
+	reply := tgbotapi.NewMessage(chatID, sb.String())
+	reply.ReplyMarkup = AddPhotoKeyboard()
+	h.bot.Send(reply)
+}
@@ -0,0 +1,227 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/archive"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// PhotoUploader загружает фото в Telegram, отправляя их в чат администратора,
+// и сразу удаляет сообщение — остаётся только file_id
+type PhotoUploader struct {
+	api    *tgbotapi.BotAPI
+	chatID int64
+}
+
+func NewPhotoUploader(api *tgbotapi.BotAPI, chatID int64) *PhotoUploader {
+	return &PhotoUploader{api: api, chatID: chatID}
+}
+
+func (u *PhotoUploader) Upload(ctx context.Context, name string, data []byte) (string, error) {
+	msg, err := u.api.Send(tgbotapi.NewPhoto(u.chatID, tgbotapi.FileBytes{Name: path.Base(name), Bytes: data}))
+	if err != nil {
+		return "", fmt.Errorf("send photo: %w", err)
+	}
+	if len(msg.Photo) == 0 {
+		return "", errors.New("telegram did not return the photo")
+	}
+
+	u.api.Request(tgbotapi.NewDeleteMessage(u.chatID, msg.MessageID))
+
+	// Берём фото максимального размера
+	return msg.Photo[len(msg.Photo)-1].FileID, nil
+}
+
+func (h *Handler) cmdImport(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	text := "📦 *Импорт ситуаций из архива*\n\n" +
+		"Отправьте боту ZIP-файл (до 20 МБ) с манифестом и папками фото.\n\n" +
+		"*manifest.csv* — колонки `answer`, `folder`, `photos`, `difficulty`, `decks`; " +
+		"несколько фото или колод в ячейке разделяются `|`:\n" +
+		"`answer,folder,difficulty,decks`\n" +
+		"`Кот на крыше,cat,1,Животные`\n\n" +
+		"*manifest.json*:\n" +
+		"`{\"situations\": [{\"answer\": \"Кот на крыше\", \"folder\": \"cat\", \"difficulty\": 1, \"decks\": [\"Животные\"]}]}`\n\n" +
+		"Если указана папка, берутся все изображения из неё по алфавиту (JPEG, PNG или WebP, не больше 5). " +
+		"Сложность: 1 — лёгкая, 2 — средняя (по умолчанию), 3 — сложная. Колоды должны быть созданы заранее.\n\n" +
+		"Архив из /export восстанавливается так же — просто отправьте его боту."
+
+	// В группе документы — обычная переписка, поэтому импортируется только следующий архив администратора
+	if !msg.Chat.IsPrivate() {
+		h.importChatsMu.Lock()
+		h.importChats[msg.From.ID] = msg.Chat.ID
+		h.importChatsMu.Unlock()
+
+		text += "\n\nВ этом чате импортируется следующий файл, который вы пришлёте."
+	}
+
+	h.sendText(msg.Chat.ID, text)
+}
+
+// takeImport сообщает, нужно ли импортировать присланный документ: в личном чате — всегда,
+// в группе — только первый файл после /import от того же пользователя
+func (h *Handler) takeImport(msg *tgbotapi.Message) bool {
+	if msg.Chat.IsPrivate() {
+		return true
+	}
+
+	h.importChatsMu.Lock()
+	defer h.importChatsMu.Unlock()
+
+	chatID, ok := h.importChats[msg.From.ID]
+	if !ok || chatID != msg.Chat.ID {
+		return false
+	}
+	delete(h.importChats, msg.From.ID)
+	return true
+}
+
+// handleImportDocument импортирует ситуации из присланного ZIP-архива и отвечает отчётом
+func (h *Handler) handleImportDocument(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		return
+	}
+
+	doc := msg.Document
+	if !strings.EqualFold(path.Ext(doc.FileName), ".zip") {
+		h.sendText(msg.Chat.ID, "Для импорта ситуаций отправьте ZIP-архив. Формат: /import")
+		return
+	}
+	if doc.FileSize > maxFileSize {
+		h.sendText(msg.Chat.ID, "❌ Архив больше 20 МБ — Telegram не отдаёт ботам такие файлы. Разбейте его на части")
+		return
+	}
+
+	h.sendText(msg.Chat.ID, "⏳ Импортирую архив, это может занять пару минут...")
+
+	// Загрузка фото долгая, поэтому не задерживаем остальные обновления
+	go func() {
+		data, _, err := NewFileDownloader(h.bot).Fetch(ctx, doc.FileID)
+		if err != nil {
+			log.Printf("Error downloading archive: %v", err)
+			h.sendText(msg.Chat.ID, "❌ Не удалось скачать архив")
+			return
+		}
+
+		a, err := archive.Open(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			h.sendText(msg.Chat.ID, "❌ "+tgbotapi.EscapeText(tgbotapi.ModeMarkdown, err.Error()))
+			return
+		}
+
+		results, err := h.importer.Import(ctx, a, NewPhotoUploader(h.bot, msg.Chat.ID))
+		if err != nil {
+			log.Printf("Error importing archive: %v", err)
+			h.sendText(msg.Chat.ID, "❌ Ситуации не сохранены: "+tgbotapi.EscapeText(tgbotapi.ModeMarkdown, err.Error()))
+			return
+		}
+
+		h.sendImportReport(msg.Chat.ID, results)
+	}()
+}
+
+// sendImportReport присылает итоги импорта; длинный отчёт делится на несколько сообщений
+func (h *Handler) sendImportReport(chatID int64, results []service.ImportItemResult) {
+	created := 0
+	for _, r := range results {
+		if r.Err == nil {
+			created++
+		}
+	}
+
+	lines := []string{fmt.Sprintf("📦 *Импорт завершён*\n\nСоздано ситуаций: %d из %d\n", created, len(results))}
+	for _, r := range results {
+		answer := r.Answer
+		if answer == "" {
+			answer = "без ответа"
+		}
+		if r.Err != nil {
+			lines = append(lines, fmt.Sprintf("❌ %d. %s — %s", r.Line, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, answer), tgbotapi.EscapeText(tgbotapi.ModeMarkdown, r.Err.Error())))
+		} else {
+			lines = append(lines, fmt.Sprintf("✅ %d. %s — #%d, %d 📷", r.Line, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, answer), r.SituationID, r.Photos))
+			if len(r.Duplicates) > 0 {
+				lines = append(lines, "    "+duplicateWarning(r.Duplicates))
+			}
+		}
+	}
+
+	// Лимит Telegram — 4096 символов в сообщении
+	var sb strings.Builder
+	for _, line := range lines {
+		if sb.Len()+len(line) > 4000 {
+			h.sendText(chatID, sb.String())
+			sb.Reset()
+		}
+		sb.WriteString(line + "\n")
+	}
+	h.sendText(chatID, sb.String())
+}
+
+// maxUploadSize — Bot API принимает от ботов документы не больше 50 МБ
+const maxUploadSize = 50 << 20
+
+func (h *Handler) cmdExport(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	h.sendText(msg.Chat.ID, "⏳ Собираю архив со всеми ситуациями и фото...")
+
+	// Фото, которых нет в хранилище, скачиваются из Telegram — это долго
+	go func() {
+		f, err := os.CreateTemp("", "photo-quiz-export-*.zip")
+		if err != nil {
+			log.Printf("Error creating export file: %v", err)
+			h.sendText(msg.Chat.ID, "❌ Не удалось создать архив")
+			return
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		result, err := h.exporter.Export(ctx, f)
+		if err != nil {
+			log.Printf("Error exporting library: %v", err)
+			h.sendText(msg.Chat.ID, "❌ Не удалось выгрузить ситуации")
+			return
+		}
+		for _, err := range result.Failed {
+			log.Printf("Error exporting photo: %v", err)
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			log.Printf("Error exporting library: %v", err)
+			h.sendText(msg.Chat.ID, "❌ Не удалось выгрузить ситуации")
+			return
+		}
+		if info.Size() > maxUploadSize {
+			h.sendText(msg.Chat.ID, fmt.Sprintf("❌ Архив занимает %d МБ, а Telegram принимает от ботов не больше 50 МБ. Выгрузите библиотеку на сервере: `bot export ФАЙЛ`", info.Size()>>20))
+			return
+		}
+
+		doc := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FilePath(f.Name()))
+		doc.Caption = fmt.Sprintf("📦 Ситуаций: %d, фото: %d\n\nЧтобы восстановить библиотеку, отправьте этот архив боту", result.Situations, result.Photos)
+		if len(result.Failed) > 0 {
+			doc.Caption += fmt.Sprintf("\n\n⚠️ Не удалось получить фото: %d", len(result.Failed))
+		}
+		if _, err := h.bot.Send(doc); err != nil {
+			log.Printf("Error sending export: %v", err)
+			h.sendText(msg.Chat.ID, "❌ Не удалось отправить архив")
+		}
+	}()
+}
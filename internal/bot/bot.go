@@ -5,9 +5,8 @@ import (
 	"log"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
 	"github.com/plastinin/photo-quiz-bot/internal/service"
-	"github.com/plastinin/photo-quiz-bot/internal/web"
 )
 
 type Bot struct {
@@ -15,15 +14,10 @@ type Bot struct {
 	handler *Handler
 }
 
-func New(token string, game *service.GameService, repo *postgres.SituationRepository, adminID int64, webServer *web.Server) (*Bot, error) {
-	api, err := tgbotapi.NewBotAPI(token)
-	if err != nil {
-		return nil, err
-	}
-
+func New(api *tgbotapi.BotAPI, game *service.GameService, repo domain.SituationRepository, decks domain.DeckRepository, photos *service.PhotoService, importer *service.ImportService, exporter *service.ExportService, users *service.UserService, drafts *service.DraftService, duplicates *service.DuplicateService, submissions *service.SubmissionService, guesses *service.GuessService, admin AdminLinker) (*Bot, error) {
 	log.Printf("Authorized on account %s", api.Self.UserName)
 
-	handler := NewHandler(api, game, repo, adminID, webServer)
+	handler := NewHandler(api, game, repo, decks, photos, importer, exporter, users, drafts, duplicates, submissions, guesses, admin)
 
 	return &Bot{
 		api:     api,
@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+func (h *Handler) cmdDecks(ctx context.Context, msg *tgbotapi.Message) {
+	decks, err := h.decks.List(ctx)
+	if err != nil {
+		log.Printf("Error listing decks: %v", err)
+		h.sendText(msg.Chat.ID, "Ошибка получения колод")
+		return
+	}
+
+	if len(decks) == 0 {
+		h.sendText(msg.Chat.ID, "Колод пока нет. Администратор может создать их командой /newdeck")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🗂 *Колоды*\n\n")
+	for _, d := range decks {
+		total, used, err := h.repo.GetStats(ctx, domain.SituationFilter{DeckIDs: []int{d.ID}})
+		if err != nil {
+			log.Printf("Error getting stats of deck %d: %v", d.ID, err)
+		}
+
+		sb.WriteString(fmt.Sprintf("• *%s* — ситуаций: %d, осталось: %d\n", d.Name, total, total-used))
+		if d.Description != "" {
+			sb.WriteString("  " + d.Description + "\n")
+		}
+	}
+	sb.WriteString("\nИграть колодой: `/start НАЗВАНИЕ`")
+
+	h.sendText(msg.Chat.ID, sb.String())
+}
+
+func (h *Handler) cmdNewDeck(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	name, description, _ := strings.Cut(msg.CommandArguments(), "|")
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	if name == "" || strings.Contains(name, ",") {
+		h.sendText(msg.Chat.ID, "Укажите название колоды (без запятых): `/newdeck Название | описание`")
+		return
+	}
+
+	deck, err := h.decks.Create(ctx, name, description)
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			h.sendText(msg.Chat.ID, "❌ Колода с таким названием уже есть")
+			return
+		}
+		log.Printf("Error creating deck: %v", err)
+		h.sendText(msg.Chat.ID, "Ошибка создания колоды")
+		return
+	}
+
+	h.sendText(msg.Chat.ID, fmt.Sprintf("✅ Колода *%s* создана\n\nДобавляйте в неё ситуации через /add", deck.Name))
+}
+
+// cbAddDeck включает или выключает колоду для добавляемой ситуации
+func (h *Handler) cbAddDeck(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.canAdd(ctx, cb.From.ID) {
+		return
+	}
+
+	deckID, err := strconv.Atoi(strings.TrimPrefix(cb.Data, "add_deck_"))
+	if err != nil {
+		return
+	}
+
+	h.updateAddOptions(ctx, cb, func(state *AddSituationState) {
+		if state.Suggest {
+			return
+		}
+		if i := slices.Index(state.DeckIDs, deckID); i >= 0 {
+			state.DeckIDs = slices.Delete(state.DeckIDs, i, i+1)
+		} else {
+			state.DeckIDs = append(state.DeckIDs, deckID)
+		}
+	})
+}
+
+// updateAddOptions меняет черновик добавляемой ситуации и перерисовывает клавиатуру выбора
+func (h *Handler) updateAddOptions(ctx context.Context, cb *tgbotapi.CallbackQuery, update func(state *AddSituationState)) {
+	h.addStateMu.Lock()
+	state, exists := h.addState[cb.From.ID]
+	var selected []int
+	var difficulty int
+	if exists {
+		update(state)
+		selected = append(selected, state.DeckIDs...)
+		difficulty = state.Difficulty
+	}
+	h.addStateMu.Unlock()
+
+	if !exists {
+		return
+	}
+	h.saveAddDraft(ctx, cb.From.ID)
+
+	var decks []domain.Deck
+	if !state.Suggest {
+		var err error
+		decks, err = h.decks.List(ctx)
+		if err != nil {
+			log.Printf("Error listing decks: %v", err)
+			return
+		}
+	}
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(cb.Message.Chat.ID, cb.Message.MessageID, AddOptionsKeyboard(decks, selected, difficulty))
+	h.bot.Send(edit)
+}
+
+// selectDecks задаёт колоды игры в чате по списку названий через запятую; false — если колоды не выбраны
+func (h *Handler) selectDecks(ctx context.Context, chatID int64, key, args string) bool {
+	filter := h.game.Filter(key)
+
+	if strings.EqualFold(args, "все") || strings.EqualFold(args, "all") {
+		filter.DeckIDs = nil
+		h.game.SetFilter(key, filter)
+		return true
+	}
+
+	var deckIDs []int
+	for _, name := range strings.Split(args, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		deck, err := h.decks.GetByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				h.sendText(chatID, fmt.Sprintf("❌ Колода «%s» не найдена. Список колод: /decks", name))
+				return false
+			}
+			log.Printf("Error getting deck %q: %v", name, err)
+			h.sendText(chatID, "Произошла ошибка. Попробуйте позже.")
+			return false
+		}
+		deckIDs = append(deckIDs, deck.ID)
+	}
+
+	filter.DeckIDs = deckIDs
+	h.game.SetFilter(key, filter)
+	h.sendText(chatID, "🗂 Колоды: "+h.deckNames(ctx, deckIDs, "все"))
+	return true
+}
+
+// deckNames перечисляет названия колод через запятую; для пустого списка возвращает empty
+func (h *Handler) deckNames(ctx context.Context, deckIDs []int, empty string) string {
+	if len(deckIDs) == 0 {
+		return empty
+	}
+
+	decks, err := h.decks.List(ctx)
+	if err != nil {
+		log.Printf("Error listing decks: %v", err)
+		return empty
+	}
+
+	var names []string
+	for _, d := range decks {
+		if slices.Contains(deckIDs, d.ID) {
+			names = append(names, d.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// difficultyNames — как можно назвать уровень сложности в /difficulty
+var difficultyNames = map[string]int{
+	"1": domain.DifficultyEasy, "лёгкая": domain.DifficultyEasy, "легкая": domain.DifficultyEasy,
+	"лёгкие": domain.DifficultyEasy, "легкие": domain.DifficultyEasy, "easy": domain.DifficultyEasy,
+	"2": domain.DifficultyMedium, "средняя": domain.DifficultyMedium, "средние": domain.DifficultyMedium,
+	"medium": domain.DifficultyMedium,
+	"3": domain.DifficultyHard, "сложная": domain.DifficultyHard, "сложные": domain.DifficultyHard,
+	"hard": domain.DifficultyHard,
+}
+
+// cmdDifficulty ограничивает игру в чате уровнями сложности или включает их чередование
+func (h *Handler) cmdDifficulty(ctx context.Context, msg *tgbotapi.Message) {
+	if _, attached := h.game.AttachedSession(msg.Chat.ID); attached {
+		h.sendText(msg.Chat.ID, "Сложность веб-комнаты выбирается при её создании")
+		return
+	}
+
+	key := h.game.KeyForChat(msg.Chat.ID)
+	filter := h.game.Filter(key)
+
+	args := strings.Fields(strings.ToLower(strings.ReplaceAll(msg.CommandArguments(), ",", " ")))
+	if len(args) == 0 {
+		h.sendText(msg.Chat.ID, fmt.Sprintf("Сейчас: %s\n\nУкажите сложность: `/difficulty лёгкие, средние, сложные`, "+
+			"`/difficulty чередовать` или `/difficulty все`", filterDifficultyLabel(filter)))
+		return
+	}
+
+	filter.Difficulties = nil
+	filter.Balanced = false
+	for _, arg := range args {
+		switch arg {
+		case "все", "all":
+		case "чередовать", "баланс", "balanced":
+			filter.Balanced = true
+		default:
+			level, ok := difficultyNames[arg]
+			if !ok {
+				h.sendText(msg.Chat.ID, fmt.Sprintf("❌ Не знаю сложность «%s». Используйте: лёгкие, средние, сложные, чередовать, все", arg))
+				return
+			}
+			if !slices.Contains(filter.Difficulties, level) {
+				filter.Difficulties = append(filter.Difficulties, level)
+			}
+		}
+	}
+	slices.Sort(filter.Difficulties)
+
+	h.game.SetFilter(key, filter)
+	h.sendText(msg.Chat.ID, "🎚 Сложность: "+filterDifficultyLabel(filter))
+}
+
+// cbAddDifficulty выбирает сложность добавляемой ситуации
+func (h *Handler) cbAddDifficulty(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.canAdd(ctx, cb.From.ID) {
+		return
+	}
+
+	level, err := strconv.Atoi(strings.TrimPrefix(cb.Data, "add_diff_"))
+	if err != nil || !slices.Contains(domain.Difficulties, level) {
+		return
+	}
+
+	h.updateAddOptions(ctx, cb, func(state *AddSituationState) {
+		state.Difficulty = level
+	})
+}
+
+func difficultyLabel(difficulty int) string {
+	switch difficulty {
+	case domain.DifficultyEasy:
+		return "лёгкая"
+	case domain.DifficultyHard:
+		return "сложная"
+	default:
+		return "средняя"
+	}
+}
+
+// filterDifficultyLabel описывает, какими сложностями идёт игра
+func filterDifficultyLabel(filter domain.SituationFilter) string {
+	levels := filter.Difficulties
+	if len(levels) == 0 {
+		levels = domain.Difficulties
+	}
+
+	var names []string
+	for _, level := range levels {
+		names = append(names, fmt.Sprintf("%s ×%g", difficultyLabel(level), service.DifficultyMultiplier(level)))
+	}
+
+	label := strings.Join(names, ", ")
+	if len(filter.Difficulties) == 0 && !filter.Balanced {
+		label = "любая (" + label + ")"
+	}
+	if filter.Balanced {
+		label += ", по очереди"
+	}
+	return label
+}
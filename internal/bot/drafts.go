@@ -0,0 +1,236 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// draftCleanupInterval — как часто убирать брошенные черновики
+const draftCleanupInterval = 10 * time.Minute
+
+// saveAddDraft сохраняет черновик /add пользователя в базе
+func (h *Handler) saveAddDraft(ctx context.Context, userID int64) {
+	h.addStateMu.Lock()
+	state, ok := h.addState[userID]
+	var draft AddSituationState
+	if ok {
+		state.UpdatedAt = time.Now()
+		draft = AddSituationState{
+			Answer:     state.Answer,
+			Photos:     slices.Clone(state.Photos),
+			DeckIDs:    slices.Clone(state.DeckIDs),
+			Difficulty: state.Difficulty,
+			UniqueIDs:  maps.Clone(state.UniqueIDs),
+			Suggest:    state.Suggest,
+			ChatID:     state.ChatID,
+		}
+	}
+	h.addStateMu.Unlock()
+
+	if !ok {
+		return
+	}
+	if err := h.drafts.Save(ctx, userID, domain.DraftAdd, &draft); err != nil {
+		log.Printf("Error saving add draft of %d: %v", userID, err)
+	}
+}
+
+// restoreAddState возвращает черновик /add, который не удалось сохранить, если пользователь
+// ещё не начал новый
+func (h *Handler) restoreAddState(ctx context.Context, userID int64, state *AddSituationState) {
+	h.addStateMu.Lock()
+	_, started := h.addState[userID]
+	if !started {
+		h.addState[userID] = state
+	}
+	h.addStateMu.Unlock()
+
+	if !started {
+		h.saveAddDraft(ctx, userID)
+	}
+}
+
+// addDraft возвращает копию незавершённого черновика /add: из памяти или, после
+// перезапуска, из базы. nil — если продолжать нечего.
+func (h *Handler) addDraft(ctx context.Context, userID int64) *AddSituationState {
+	h.addStateMu.RLock()
+	state, inMemory := h.addState[userID]
+	var draft *AddSituationState
+	if inMemory && (state.Answer != "" || len(state.Photos) > 0) {
+		draft = &AddSituationState{
+			Answer:     state.Answer,
+			Photos:     slices.Clone(state.Photos),
+			DeckIDs:    slices.Clone(state.DeckIDs),
+			Difficulty: state.Difficulty,
+			UniqueIDs:  maps.Clone(state.UniqueIDs),
+			Suggest:    state.Suggest,
+			ChatID:     state.ChatID,
+			Waiting:    true,
+			UpdatedAt:  state.UpdatedAt,
+		}
+	}
+	h.addStateMu.RUnlock()
+
+	if inMemory {
+		return draft
+	}
+
+	var saved AddSituationState
+	updatedAt, err := h.drafts.Load(ctx, userID, domain.DraftAdd, &saved)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Error loading add draft of %d: %v", userID, err)
+		}
+		return nil
+	}
+	if saved.Answer == "" && len(saved.Photos) == 0 {
+		return nil
+	}
+	saved.Waiting = true
+	saved.UpdatedAt = updatedAt
+	return &saved
+}
+
+func draftSummary(draft *AddSituationState) string {
+	answer := "ещё не указан"
+	if draft.Answer != "" {
+		answer = "*" + tgbotapi.EscapeText(tgbotapi.ModeMarkdown, draft.Answer) + "*"
+	}
+	return fmt.Sprintf("ответ %s, фото: %d из %d", answer, len(draft.Photos), domain.MaxPhotosPerSituation)
+}
+
+// cbResumeAdd продолжает незавершённый черновик /add или /suggest
+func (h *Handler) cbResumeAdd(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	draft := h.addDraft(ctx, cb.From.ID)
+	if draft == nil {
+		h.sendText(chatID, "Черновик уже не найден. Начните заново: /add или /suggest")
+		return
+	}
+	if !draft.Suggest && !h.can(ctx, cb.From.ID, domain.PermManageContent) {
+		return
+	}
+
+	h.addStateMu.Lock()
+	if state, ok := h.addState[cb.From.ID]; ok {
+		state.ChatID = chatID
+	} else {
+		draft.ChatID = chatID
+		h.addState[cb.From.ID] = draft
+	}
+	h.addStateMu.Unlock()
+
+	if draft.Answer == "" {
+		reply := tgbotapi.NewMessage(chatID, "📝 Введите правильный ответ (что изображено на фото):")
+		reply.ReplyMarkup = CancelAddKeyboard()
+		h.bot.Send(reply)
+		return
+	}
+
+	var decks []domain.Deck
+	if !draft.Suggest {
+		var err error
+		decks, err = h.decks.List(ctx)
+		if err != nil {
+			log.Printf("Error listing decks: %v", err)
+		}
+	}
+
+	text := "Выберите сложность и отправьте фотографии"
+	if len(decks) > 0 {
+		text = "Выберите сложность, колоды и отправьте фотографии"
+	}
+
+	reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("📝 Продолжаем черновик: %s\n\n%s", draftSummary(draft), text))
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = AddOptionsKeyboard(decks, draft.DeckIDs, draft.Difficulty)
+	h.bot.Send(reply)
+
+	if len(draft.Photos) > 0 {
+		reply := tgbotapi.NewMessage(chatID, "Можете отправить ещё фото или нажмите кнопку для завершения")
+		reply.ReplyMarkup = AddPhotoKeyboard()
+		h.bot.Send(reply)
+	}
+}
+
+// cbDiscardAdd удаляет черновик и начинает заново /add или /suggest
+func (h *Handler) cbDiscardAdd(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	suggest := cb.Data == "add_discard_suggest"
+	if !suggest && !h.can(ctx, cb.From.ID, domain.PermManageContent) {
+		return
+	}
+
+	h.startAdd(ctx, cb.From.ID, cb.Message.Chat.ID, suggest)
+}
+
+func (h *Handler) saveScoreDraft(ctx context.Context, userID int64, state *ScoreInputState) {
+	if err := h.drafts.Save(ctx, userID, domain.DraftScore, state); err != nil {
+		log.Printf("Error saving score draft of %d: %v", userID, err)
+	}
+}
+
+// loadScoreStates восстанавливает ожидание BazuCoin, сохранённое до перезапуска
+func (h *Handler) loadScoreStates(ctx context.Context) {
+	drafts, err := h.drafts.List(ctx, domain.DraftScore)
+	if err != nil {
+		log.Printf("Error loading score drafts: %v", err)
+		return
+	}
+
+	h.scoreStateMu.Lock()
+	defer h.scoreStateMu.Unlock()
+
+	for _, d := range drafts {
+		var state ScoreInputState
+		if err := json.Unmarshal(d.Data, &state); err != nil {
+			log.Printf("Error decoding score draft of %d: %v", d.UserID, err)
+			continue
+		}
+		state.Waiting = true
+		state.UpdatedAt = d.UpdatedAt
+		h.scoreState[d.UserID] = &state
+	}
+}
+
+// expireDrafts периодически забывает брошенные черновики в памяти и удаляет их из базы
+func (h *Handler) expireDrafts() {
+	ticker := time.NewTicker(draftCleanupInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		h.addStateMu.Lock()
+		for id, state := range h.addState {
+			if h.drafts.Expired(state.UpdatedAt, now) {
+				state.album.stop()
+				delete(h.addState, id)
+			}
+		}
+		h.addStateMu.Unlock()
+
+		h.scoreStateMu.Lock()
+		for id, state := range h.scoreState {
+			if h.drafts.Expired(state.UpdatedAt, now) {
+				delete(h.scoreState, id)
+			}
+		}
+		h.scoreStateMu.Unlock()
+
+		count, err := h.drafts.Cleanup(context.Background())
+		if err != nil {
+			log.Printf("Error cleaning up drafts: %v", err)
+			continue
+		}
+		if count > 0 {
+			log.Printf("Removed %d expired drafts", count)
+		}
+	}
+}
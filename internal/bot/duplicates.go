@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// duplicateWarning описывает уже добавленные похожие ситуации; пусто, если их нет
+func duplicateWarning(duplicates []service.Duplicate) string {
+	if len(duplicates) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, d := range duplicates {
+		switch d.Reason {
+		case service.DuplicateAnswer:
+			parts = append(parts, fmt.Sprintf("такой же ответ у #%d", d.SituationID))
+		case service.DuplicateFile:
+			parts = append(parts, fmt.Sprintf("фото %d уже есть в #%d", d.Photo, d.SituationID))
+		case service.DuplicateImage:
+			parts = append(parts, fmt.Sprintf("фото %d похоже на фото из #%d", d.Photo, d.SituationID))
+		}
+	}
+	return "⚠️ Возможно, дубль: " + strings.Join(parts, "; ") + " (посмотреть: /show ID)"
+}
+
+// photoDuplicates ищет в библиотеке фото, совпадающее с присланным; number — номер фото в черновике
+func (h *Handler) photoDuplicates(ctx context.Context, photo tgbotapi.PhotoSize, number int) []service.Duplicate {
+	fp, err := h.photos.FingerprintTelegramPhoto(ctx, photo.FileID, photo.FileUniqueID)
+	if err != nil {
+		// Без хеша проверяем хотя бы file_unique_id
+		log.Printf("Error fingerprinting photo: %v", err)
+	}
+
+	duplicates, err := h.duplicates.Find(ctx, "", []service.PhotoFingerprint{fp})
+	if err != nil {
+		log.Printf("Error finding duplicate photos: %v", err)
+		return nil
+	}
+	for i := range duplicates {
+		duplicates[i].Photo = number
+	}
+	return duplicates
+}
+
+// answerDuplicates ищет в библиотеке ситуации с таким же ответом
+func (h *Handler) answerDuplicates(ctx context.Context, answer string) []service.Duplicate {
+	duplicates, err := h.duplicates.Find(ctx, answer, nil)
+	if err != nil {
+		log.Printf("Error finding duplicate answers: %v", err)
+		return nil
+	}
+	return duplicates
+}
+
+// addPhoto добавляет фото в черновик; вызывать под addStateMu
+func (s *AddSituationState) addPhoto(photo tgbotapi.PhotoSize) {
+	s.Photos = append(s.Photos, photo.FileID)
+	if photo.FileUniqueID != "" {
+		if s.UniqueIDs == nil {
+			s.UniqueIDs = make(map[string]string)
+		}
+		s.UniqueIDs[photo.FileID] = photo.FileUniqueID
+	}
+}
+
+// savePhotoUniqueIDs запоминает file_unique_id фото новой ситуации
+func (h *Handler) savePhotoUniqueIDs(ctx context.Context, situationID int, uniqueIDs map[string]string) {
+	if len(uniqueIDs) == 0 {
+		return
+	}
+
+	situation, err := h.repo.GetByID(ctx, situationID)
+	if err != nil {
+		log.Printf("Error getting situation %d: %v", situationID, err)
+		return
+	}
+	for _, p := range situation.Photos {
+		if id := uniqueIDs[p.FileID]; id != "" {
+			if err := h.repo.SetPhotoFingerprint(ctx, p.ID, id, 0); err != nil {
+				log.Printf("Error saving fingerprint of photo %d: %v", p.ID, err)
+			}
+		}
+	}
+}
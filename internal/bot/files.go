@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxFileSize — Bot API отдаёт ботам файлы не больше 20 МБ
+const maxFileSize = 20 << 20
+
+// FileDownloader скачивает файлы из Telegram по file_id
+type FileDownloader struct {
+	api *tgbotapi.BotAPI
+}
+
+func NewFileDownloader(api *tgbotapi.BotAPI) *FileDownloader {
+	return &FileDownloader{api: api}
+}
+
+func (d *FileDownloader) Fetch(ctx context.Context, fileID string) ([]byte, string, error) {
+	file, err := d.api.GetFile(tgbotapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return nil, "", fmt.Errorf("get file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.Link(d.api.Token), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("download file: unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("read file: %w", err)
+	}
+	if len(data) > maxFileSize {
+		return nil, "", fmt.Errorf("file is larger than %d bytes", maxFileSize)
+	}
+
+	return data, file.FilePath, nil
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// cmdGuess включает и выключает режим угадывания: /guess вкл, /guess выкл, без аргументов — переключает
+func (h *Handler) cmdGuess(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	if _, attached := h.game.AttachedSession(chatID); attached {
+		h.sendText(chatID, "В игре веб-комнаты BazuCoin начисляет судья — режим угадывания здесь не работает")
+		return
+	}
+
+	on := !h.guesses.Enabled(chatID, !msg.Chat.IsPrivate())
+	switch strings.ToLower(strings.TrimSpace(msg.CommandArguments())) {
+	case "":
+	case "вкл", "on":
+		on = true
+	case "выкл", "off":
+		on = false
+	default:
+		h.sendText(chatID, "Используйте /guess вкл или /guess выкл")
+		return
+	}
+
+	h.guesses.SetEnabled(chatID, on)
+	if on {
+		h.sendText(chatID, "✍️ Режим угадывания включён: пишите ответы в чат, первый угадавший получит BazuCoin\n\nРейтинг игроков — /top")
+	} else {
+		h.sendText(chatID, "🔇 Режим угадывания выключен: ответы в чате больше не проверяются")
+	}
+}
+
+// handleGuess проверяет сообщение игрока как догадку; верная догадка завершает раунд
+func (h *Handler) handleGuess(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	if !h.guesses.Enabled(chatID, !msg.Chat.IsPrivate()) {
+		return
+	}
+
+	name := displayName(msg.From)
+	if name == "" {
+		name = "игрок"
+	}
+
+	result, err := h.guesses.Check(ctx, chatID, msg.From.ID, name, msg.Text)
+	if err != nil {
+		if result == nil {
+			// Раунд не начат или уже угадан — это просто разговор в чате
+			if !errors.Is(err, service.ErrGameNotStarted) && !errors.Is(err, service.ErrAnswerShown) && !errors.Is(err, service.ErrGuessingOff) {
+				log.Printf("Error checking guess: %v", err)
+			}
+			return
+		}
+		log.Printf("Error saving guess: %v", err)
+	}
+	if !result.Correct {
+		return
+	}
+
+	text := fmt.Sprintf("🎉 Правильно, *%s*!\n\nОтвет: *%s*\n+%g 🤑 · фото: %d · неверных догадок: %d",
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, name),
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, result.Answer),
+		result.Points, result.PhotosShown, result.WrongGuesses)
+	if result.HintsShown > 0 {
+		text += fmt.Sprintf(" · подсказок: %d", result.HintsShown)
+	}
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ParseMode = "Markdown"
+	reply.ReplyToMessageID = msg.MessageID
+	h.bot.Send(reply)
+
+	h.nextTurn(ctx, chatID)
+}
+
+// cmdTop показывает рейтинг игроков чата в режиме угадывания; /top сброс — обнуляет его
+func (h *Handler) cmdTop(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+
+	if args := strings.ToLower(strings.TrimSpace(msg.CommandArguments())); args == "сброс" || args == "reset" {
+		if !h.can(ctx, msg.From.ID, domain.PermScore) {
+			h.sendText(chatID, "⛔ Сбросить рейтинг может только судья или администратор")
+			return
+		}
+		if err := h.guesses.ResetScores(ctx, chatID); err != nil {
+			log.Printf("Error resetting chat scores: %v", err)
+			h.sendText(chatID, "Ошибка сброса рейтинга")
+			return
+		}
+		h.sendText(chatID, "🧹 Рейтинг чата обнулён")
+		return
+	}
+
+	scores, err := h.guesses.Scoreboard(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting chat scores: %v", err)
+		h.sendText(chatID, "Ошибка получения рейтинга")
+		return
+	}
+	if len(scores) == 0 {
+		h.sendText(chatID, "🏆 В этом чате ещё никто не угадывал. Включите режим угадывания: /guess вкл")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🏆 *Рейтинг чата*\n\n")
+	for i, s := range scores {
+		sb.WriteString(fmt.Sprintf("%d. %s — %g 🤑 (угадано: %d, мимо: %d)\n",
+			i+1, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, s.Name), s.Score, s.Correct, s.Wrong))
+	}
+	h.sendText(chatID, sb.String())
+}
@@ -2,78 +2,174 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
 	"github.com/plastinin/photo-quiz-bot/internal/service"
-	"github.com/plastinin/photo-quiz-bot/internal/web"
 )
 
 type Handler struct {
 	bot     *tgbotapi.BotAPI
 	game    *service.GameService
-	repo    *postgres.SituationRepository
-	adminID int64
-	web     *web.Server
+	repo    domain.SituationRepository
+	decks   domain.DeckRepository
+	photos   *service.PhotoService
+	importer *service.ImportService
+	exporter *service.ExportService
+	users    *service.UserService
+	drafts   *service.DraftService
+	duplicates *service.DuplicateService
+	submissions *service.SubmissionService
+	guesses  *service.GuessService
+	admin    AdminLinker
 
 	// Состояние добавления ситуации
 	addState   map[int64]*AddSituationState
 	addStateMu sync.RWMutex
 
+	// Состояние редактирования ситуации
+	editState   map[int64]*EditSituationState
+	editStateMu sync.RWMutex
+
 	// Состояние ввода очков
 	scoreState   map[int64]*ScoreInputState
 	scoreStateMu sync.RWMutex
+
+	// Групповые чаты, в которые администратор после /import пришлёт архив; ключ — ID пользователя
+	importChats   map[int64]int64
+	importChatsMu sync.Mutex
+
+	// Последние фото раундов в чатах: в их подписи идёт обратный отсчёт таймера
+	gameMessages   map[int64]gameMessage
+	gameMessagesMu sync.Mutex
 }
 
+// AddSituationState — черновик /add; поля меняются только под addStateMu.
+// Сохраняется в базе (DraftAdd), чтобы после перезапуска его можно было продолжить.
 type AddSituationState struct {
-	Answer  string
-	Photos  []string
-	Waiting bool
+	Answer     string    `json:"answer"`
+	Photos     []string  `json:"photos,omitempty"`
+	DeckIDs    []int     `json:"deckIds,omitempty"`
+	Difficulty int       `json:"difficulty"`
+	Waiting    bool      `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+
+	// file_unique_id присланных фото по их file_id: запоминаются для поиска дублей
+	UniqueIDs map[string]string `json:"uniqueIds,omitempty"`
+
+	// Чат, в котором идёт добавление: сообщения того же игрока в других чатах сюда не попадают.
+	// 0 — черновик, сохранённый без чата: его продолжают только в личном чате
+	ChatID int64 `json:"chatId,omitempty"`
+
+	// Черновик предложения игрока (/suggest): после завершения уходит на модерацию,
+	// а не сразу в библиотеку; не меняется после создания черновика
+	Suggest bool `json:"suggest,omitempty"`
+
+	// Альбом, фото которого ещё приходят: подтверждение отправляется одно на весь альбом
+	album *pendingAlbum
 }
 
+// EditSituationState — ожидание нового ответа или фото для существующей ситуации
+// или нового ответа для предложения игрока
+type EditSituationState struct {
+	SituationID  int
+	SubmissionID int    // предложение на модерации, если Field — "submission"
+	Field        string // "answer", "aliases", "hints", "photos" или "submission"
+	ChatID       int64  // чат, в котором открыто редактирование
+}
+
+// ScoreInputState — ожидание BazuCoin от судьи; сохраняется в базе (DraftScore)
 type ScoreInputState struct {
-	PlayerName string
-	Waiting    bool
+	PlayerName  string    `json:"playerName"`
+	SessionCode string    `json:"sessionCode"`
+	Waiting     bool      `json:"-"`
+	UpdatedAt   time.Time `json:"-"`
 }
 
-func NewHandler(bot *tgbotapi.BotAPI, game *service.GameService, repo *postgres.SituationRepository, adminID int64, webServer *web.Server) *Handler {
+func NewHandler(bot *tgbotapi.BotAPI, game *service.GameService, repo domain.SituationRepository, decks domain.DeckRepository, photos *service.PhotoService, importer *service.ImportService, exporter *service.ExportService, users *service.UserService, drafts *service.DraftService, duplicates *service.DuplicateService, submissions *service.SubmissionService, guesses *service.GuessService, admin AdminLinker) *Handler {
 	h := &Handler{
 		bot:        bot,
 		game:       game,
 		repo:       repo,
-		adminID:    adminID,
-		web:        webServer,
+		decks:      decks,
+		photos:     photos,
+		importer:   importer,
+		exporter:   exporter,
+		users:      users,
+		drafts:     drafts,
+		duplicates: duplicates,
+		submissions: submissions,
+		guesses:    guesses,
+		admin:      admin,
 		addState:   make(map[int64]*AddSituationState),
+		editState:  make(map[int64]*EditSituationState),
 		scoreState: make(map[int64]*ScoreInputState),
+		importChats: make(map[int64]int64),
+		gameMessages: make(map[int64]gameMessage),
 	}
 
-	// Слушаем события завершения хода из веба
-	if webServer != nil {
-		go h.listenTurnEndEvents()
-	}
+	// Судьи, которые не успели начислить BazuCoin до перезапуска, продолжают с того же хода
+	h.loadScoreStates(context.Background())
+
+	// Слушаем события завершения хода в веб-комнатах
+	go h.listenTurnEndEvents()
+
+	// Фото и ответы, открытые таймером раунда, и обратный отсчёт в подписях
+	go h.listenTimerEvents()
+	go h.tickTimers()
+
+	// Убираем брошенные черновики
+	go h.expireDrafts()
 
 	return h
 }
 
 func (h *Handler) listenTurnEndEvents() {
-	for event := range h.web.Session.TurnEndChan {
-		// Отправляем админу запрос на ввод очков
-		h.scoreStateMu.Lock()
-		h.scoreState[h.adminID] = &ScoreInputState{
-			PlayerName: event.PlayerName,
-			Waiting:    true,
+	for event := range h.game.TurnEndChan {
+		// Отправляем запрос на ввод очков всем, кто может их начислять
+		judges, err := h.users.WithPermission(context.Background(), domain.PermScore)
+		if err != nil {
+			log.Printf("Error listing judges: %v", err)
+			continue
 		}
-		h.scoreStateMu.Unlock()
 
-		msg := tgbotapi.NewMessage(h.adminID, fmt.Sprintf("🤑 *Ход завершён!*\n\nИгрок: *%s*\n\nВыберите количество BazuCoin:", event.PlayerName))
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = ScoreKeyboard()
-		h.bot.Send(msg)
+		for _, judgeID := range judges {
+			state := &ScoreInputState{
+				PlayerName:  event.PlayerName,
+				SessionCode: event.SessionCode,
+				Waiting:     true,
+				UpdatedAt:   time.Now(),
+			}
+			h.scoreStateMu.Lock()
+			h.scoreState[judgeID] = state
+			h.scoreStateMu.Unlock()
+			h.saveScoreDraft(context.Background(), judgeID, state)
+
+			player := event.PlayerName
+			if event.TeamName != "" {
+				player = fmt.Sprintf("%s (команда %s)", event.PlayerName, event.TeamName)
+			}
+			text := fmt.Sprintf("🤑 *Ход завершён!*\n\nКомната: *%s*\nИгрок: *%s*\nСложность: %s (×%g)\nОткрыто фото: %d",
+				event.SessionCode, player, difficultyLabel(event.Difficulty), service.DifficultyMultiplier(event.Difficulty), event.PhotosShown)
+			if event.Suggested != nil {
+				text += fmt.Sprintf("\nПо правилу комнаты: %g BazuCoin, если угадал", *event.Suggested)
+			}
+			if event.Hints > 0 {
+				text += fmt.Sprintf("\nПодсказок: %d — из выбранных очков вычтется %g", event.Hints, float64(event.Hints)*service.HintPenalty)
+			}
+			msg := tgbotapi.NewMessage(judgeID, text+"\n\nВыберите количество BazuCoin:")
+			msg.ParseMode = "Markdown"
+			msg.ReplyMarkup = ScoreKeyboard(event.SessionCode, event.Suggested)
+			h.bot.Send(msg)
+		}
 	}
 }
 
@@ -88,13 +184,23 @@ func (h *Handler) Handle(ctx context.Context, update tgbotapi.Update) {
 	}
 }
 
+// dialogChat сообщает, пришло ли сообщение в чат, где открыт диалог добавления или редактирования;
+// chatID 0 — диалог без чата (черновик до перезапуска), его ведут в личном чате
+func dialogChat(chatID int64, msg *tgbotapi.Message) bool {
+	if chatID == 0 {
+		return msg.Chat.IsPrivate()
+	}
+	return chatID == msg.Chat.ID
+}
+
 func (h *Handler) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 	// Проверяем, ожидаем ли ввод очков
 	h.scoreStateMu.RLock()
 	scoreState, hasScoreState := h.scoreState[msg.From.ID]
 	h.scoreStateMu.RUnlock()
 
-	if hasScoreState && scoreState.Waiting && msg.Text != "" {
+	// Очки судья вводит в личном чате с ботом, куда пришёл запрос; в группе его сообщения — обычные догадки
+	if hasScoreState && scoreState.Waiting && msg.Text != "" && msg.Chat.IsPrivate() {
 		h.handleScoreInput(ctx, msg, scoreState)
 		return
 	}
@@ -104,11 +210,30 @@ func (h *Handler) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 	state, hasState := h.addState[msg.From.ID]
 	h.addStateMu.RUnlock()
 
-	if hasState && state.Waiting {
+	// Команды во время добавления обрабатываются как обычно: /add предложит продолжить черновик
+	if hasState && state.Waiting && !msg.IsCommand() && dialogChat(state.ChatID, msg) {
 		h.handleAddState(ctx, msg, state)
 		return
 	}
 
+	// Проверяем, редактируется ли ситуация
+	h.editStateMu.RLock()
+	editState, hasEditState := h.editState[msg.From.ID]
+	h.editStateMu.RUnlock()
+
+	if hasEditState && !msg.IsCommand() && dialogChat(editState.ChatID, msg) {
+		h.handleEditState(ctx, msg, editState)
+		return
+	}
+
+	// ZIP-архив с ситуациями для импорта; в группах — только после /import
+	if msg.Document != nil {
+		if h.takeImport(msg) {
+			h.handleImportDocument(ctx, msg)
+		}
+		return
+	}
+
 	// Обработка команд
 	if msg.IsCommand() {
 		switch msg.Command() {
@@ -116,22 +241,62 @@ func (h *Handler) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
 			h.cmdStart(ctx, msg)
 		case "add":
 			h.cmdAdd(ctx, msg)
+		case "suggest":
+			h.cmdSuggest(ctx, msg)
+		case "suggestions":
+			h.cmdSuggestions(ctx, msg)
+		case "import":
+			h.cmdImport(ctx, msg)
+		case "export":
+			h.cmdExport(ctx, msg)
+		case "web":
+			h.cmdWeb(ctx, msg)
+		case "list":
+			h.cmdList(ctx, msg)
+		case "show":
+			h.cmdShow(ctx, msg)
 		case "reset":
 			h.cmdReset(ctx, msg)
 		case "delete":
 			h.cmdDelete(ctx, msg)
+		case "join":
+			h.cmdJoin(ctx, msg)
+		case "leave":
+			h.cmdLeave(ctx, msg)
+		case "decks":
+			h.cmdDecks(ctx, msg)
+		case "newdeck":
+			h.cmdNewDeck(ctx, msg)
+		case "difficulty":
+			h.cmdDifficulty(ctx, msg)
+		case "grant":
+			h.cmdGrant(ctx, msg)
+		case "revoke":
+			h.cmdRevoke(ctx, msg)
 		case "stats":
 			h.cmdStats(ctx, msg)
+		case "timer":
+			h.cmdTimer(ctx, msg)
+		case "guess":
+			h.cmdGuess(ctx, msg)
+		case "top":
+			h.cmdTop(ctx, msg)
 		case "help":
 			h.cmdHelp(ctx, msg)
 		default:
 			h.sendText(msg.Chat.ID, "Неизвестная команда. Используйте /help")
 		}
+		return
+	}
+
+	// Остальной текст в режиме угадывания — догадка игрока
+	if msg.Text != "" {
+		h.handleGuess(ctx, msg)
 	}
 }
 
 func (h *Handler) handleScoreInput(ctx context.Context, msg *tgbotapi.Message, state *ScoreInputState) {
-	if !h.isAdmin(msg.From.ID) {
+	if !h.can(ctx, msg.From.ID, domain.PermScore) {
 		return
 	}
 
@@ -143,31 +308,76 @@ func (h *Handler) handleScoreInput(ctx context.Context, msg *tgbotapi.Message, s
 	}
 
 	// Проверка допустимых значений
-	validScores := map[float64]bool{0: true, 0.5: true, 1: true, 1.5: true, 2: true, 2.5: true, 3: true}
-	if !validScores[score] {
+	if !service.ValidBaseScore(score) {
 		h.sendText(msg.Chat.ID, "❌ Допустимые значения: 0, 0.5, 1, 1.5, 2, 2.5, 3")
 		return
 	}
 
-	// Добавляем очки
-	playerName, totalScore, ok := h.web.AddScoreToCurrentPlayer(score)
-	if !ok {
-		h.sendText(msg.Chat.ID, "❌ Ошибка: нет активной сессии")
-		h.clearScoreState(msg.From.ID)
+	if !h.claimScore(msg.From.ID, state.SessionCode) {
+		h.sendText(msg.Chat.ID, "BazuCoin за этот ход уже начислены")
 		return
 	}
 
-	h.clearScoreState(msg.From.ID)
+	// Добавляем очки
+	result, err := h.game.AddScoreToCurrentPlayer(ctx, state.SessionCode, score)
+	if err != nil {
+		if errors.Is(err, service.ErrNoActiveSession) || errors.Is(err, service.ErrSessionNotFound) {
+			h.sendText(msg.Chat.ID, "❌ Ошибка: нет активной сессии")
+			return
+		}
+		log.Printf("Error adding score: %v", err)
+		h.sendText(msg.Chat.ID, "❌ Ошибка сохранения очков. Попробуйте ещё раз.")
+		h.restoreScoreState(msg.From.ID, state)
+		return
+	}
 
-	reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("✅ *%s* получает *%.1f* 🤑 BazuCoin!\n\nВсего: *%.1f* 🤑", playerName, score, totalScore))
-	reply.ParseMode = "Markdown"
-	h.bot.Send(reply)
+	h.sendScoreResult(msg.Chat.ID, result)
 }
 
 func (h *Handler) clearScoreState(userID int64) {
 	h.scoreStateMu.Lock()
 	delete(h.scoreState, userID)
 	h.scoreStateMu.Unlock()
+
+	h.drafts.Delete(context.Background(), userID, domain.DraftScore)
+}
+
+// claimScore закрепляет начисление очков за ход комнаты code за пользователем: запрос
+// снимается у всех судей, чтобы ход не оценили дважды. false — если запроса у пользователя
+// уже нет (очки начислил кто-то другой или ввод отменён).
+func (h *Handler) claimScore(userID int64, code string) bool {
+	h.scoreStateMu.Lock()
+	if state, ok := h.scoreState[userID]; !ok || state.SessionCode != code {
+		h.scoreStateMu.Unlock()
+		return false
+	}
+	var judges []int64
+	for id, state := range h.scoreState {
+		if state.SessionCode == code {
+			delete(h.scoreState, id)
+			judges = append(judges, id)
+		}
+	}
+	h.scoreStateMu.Unlock()
+
+	for _, id := range judges {
+		h.drafts.Delete(context.Background(), id, domain.DraftScore)
+	}
+	return true
+}
+
+// restoreScoreState возвращает пользователю запрос на ввод очков, если начислить их не удалось
+func (h *Handler) restoreScoreState(userID int64, state *ScoreInputState) {
+	h.scoreStateMu.Lock()
+	_, exists := h.scoreState[userID]
+	if !exists {
+		h.scoreState[userID] = state
+	}
+	h.scoreStateMu.Unlock()
+
+	if !exists {
+		h.saveScoreDraft(context.Background(), userID, state)
+	}
 }
 
 func (h *Handler) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
@@ -180,12 +390,18 @@ func (h *Handler) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery
 		h.cbMorePhoto(ctx, cb)
 	case cb.Data == "show_answer":
 		h.cbShowAnswer(ctx, cb)
+	case cb.Data == "show_hint":
+		h.cbShowHint(ctx, cb)
 	case cb.Data == "next_turn":
 		h.cbNextTurn(ctx, cb)
 	case cb.Data == "finish_add":
 		h.cbFinishAdd(ctx, cb)
 	case cb.Data == "cancel_add":
 		h.cbCancelAdd(ctx, cb)
+	case cb.Data == "add_resume":
+		h.cbResumeAdd(ctx, cb)
+	case cb.Data == "add_discard", cb.Data == "add_discard_suggest":
+		h.cbDiscardAdd(ctx, cb)
 	case cb.Data == "confirm_reset":
 		h.cbConfirmReset(ctx, cb)
 	case cb.Data == "cancel_reset":
@@ -196,11 +412,19 @@ func (h *Handler) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery
 		h.cbCancelDelete(ctx, cb)
 	case strings.HasPrefix(cb.Data, "score_"):
 		h.cbScoreButton(ctx, cb)
+	case strings.HasPrefix(cb.Data, "add_deck_"):
+		h.cbAddDeck(ctx, cb)
+	case strings.HasPrefix(cb.Data, "add_diff_"):
+		h.cbAddDifficulty(ctx, cb)
+	case strings.HasPrefix(cb.Data, "sit_"):
+		h.cbSituation(ctx, cb)
+	case strings.HasPrefix(cb.Data, "sub_"):
+		h.cbSubmission(ctx, cb)
 	}
 }
 
 func (h *Handler) cbScoreButton(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	if !h.isAdmin(cb.From.ID) {
+	if !h.can(ctx, cb.From.ID, domain.PermScore) {
 		return
 	}
 
@@ -210,37 +434,69 @@ func (h *Handler) cbScoreButton(ctx context.Context, cb *tgbotapi.CallbackQuery)
 		return
 	}
 
-	h.scoreStateMu.RLock()
-	state, hasState := h.scoreState[cb.From.ID]
-	h.scoreStateMu.RUnlock()
-
-	if !hasState || !state.Waiting {
+	// Формат: score_<код комнаты>_<очки>
+	parts := strings.SplitN(strings.TrimPrefix(cb.Data, "score_"), "_", 2)
+	if len(parts) != 2 {
 		return
 	}
-
-	// Парсим очки из callback data
-	scoreStr := strings.TrimPrefix(cb.Data, "score_")
-	score, err := strconv.ParseFloat(scoreStr, 64)
+	code := parts[0]
+	score, err := strconv.ParseFloat(parts[1], 64)
 	if err != nil {
 		return
 	}
 
-	playerName, totalScore, ok := h.web.AddScoreToCurrentPlayer(score)
-	if !ok {
-		h.sendText(cb.Message.Chat.ID, "❌ Ошибка: нет активной сессии")
-		h.clearScoreState(cb.From.ID)
+	// Данные кнопки приходят от клиента, поэтому проверяем их так же, как введённые вручную
+	if !service.ValidBaseScore(score) {
+		h.sendText(cb.Message.Chat.ID, "❌ Допустимые значения: 0, 0.5, 1, 1.5, 2, 2.5, 3")
 		return
 	}
 
-	h.clearScoreState(cb.From.ID)
-
 	// Удаляем клавиатуру
 	edit := tgbotapi.NewEditMessageReplyMarkup(cb.Message.Chat.ID, cb.Message.MessageID, tgbotapi.InlineKeyboardMarkup{})
+
+	h.scoreStateMu.RLock()
+	state := h.scoreState[cb.From.ID]
+	h.scoreStateMu.RUnlock()
+
+	if !h.claimScore(cb.From.ID, code) {
+		h.bot.Send(edit)
+		h.sendText(cb.Message.Chat.ID, "BazuCoin за этот ход уже начислены")
+		return
+	}
+
+	result, err := h.game.AddScoreToCurrentPlayer(ctx, code, score)
+	if err != nil {
+		if errors.Is(err, service.ErrNoActiveSession) || errors.Is(err, service.ErrSessionNotFound) {
+			h.sendText(cb.Message.Chat.ID, "❌ Ошибка: нет активной сессии")
+			return
+		}
+		log.Printf("Error adding score: %v", err)
+		h.sendText(cb.Message.Chat.ID, "❌ Ошибка сохранения очков. Попробуйте ещё раз.")
+		h.restoreScoreState(cb.From.ID, state)
+		return
+	}
+
 	h.bot.Send(edit)
 
-	reply := tgbotapi.NewMessage(cb.Message.Chat.ID, fmt.Sprintf("✅ *%s* получает *%.1f* 🤑 BazuCoin!\n\nВсего: *%.1f* 🤑", playerName, score, totalScore))
-	reply.ParseMode = "Markdown"
-	h.bot.Send(reply)
+	h.sendScoreResult(cb.Message.Chat.ID, result)
+}
+
+// sendScoreResult сообщает о начислении BazuCoin с учётом множителя сложности
+func (h *Handler) sendScoreResult(chatID int64, result *service.ScoreResult) {
+	text := fmt.Sprintf("✅ *%s* получает *%g* 🤑 BazuCoin!", result.Player.Name, result.Awarded)
+	if base := result.Base - result.Penalty; result.Multiplier != 1 && base != 0 {
+		text += fmt.Sprintf(" (%g × %g за сложность)", base, result.Multiplier)
+	}
+	if result.Penalty > 0 {
+		text += fmt.Sprintf("\n💡 Подсказок: %d, вычтено %g из %g", result.Hints, result.Penalty, result.Base)
+	}
+	if result.Team != nil {
+		text += fmt.Sprintf("\n\nКоманда *%s*: *%g* 🤑", result.Team.Name, result.TeamScore)
+	} else {
+		text += fmt.Sprintf("\n\nВсего: *%g* 🤑", result.Player.Score)
+	}
+
+	h.sendText(chatID, text)
 }
 
 func (h *Handler) cbScoreCancel(ctx context.Context, cb *tgbotapi.CallbackQuery) {
@@ -254,40 +510,110 @@ func (h *Handler) cbScoreCancel(ctx context.Context, cb *tgbotapi.CallbackQuery)
 }
 
 func (h *Handler) handleAddState(ctx context.Context, msg *tgbotapi.Message, state *AddSituationState) {
-	if !h.isAdmin(msg.From.ID) {
+	if !h.canAdd(ctx, msg.From.ID) {
 		return
 	}
 
 	// Если ещё нет ответа — ожидаем текст
-	if state.Answer == "" {
+	h.addStateMu.Lock()
+	hasAnswer := state.Answer != ""
+	if !hasAnswer && msg.Text != "" {
+		state.Answer = msg.Text
+	}
+	selected := slices.Clone(state.DeckIDs)
+	difficulty := state.Difficulty
+	h.addStateMu.Unlock()
+
+	if !hasAnswer {
 		if msg.Text == "" {
 			h.sendText(msg.Chat.ID, "Пожалуйста, введите текстовый ответ")
 			return
 		}
-		state.Answer = msg.Text
-		h.sendText(msg.Chat.ID, fmt.Sprintf("✅ Ответ сохранён: *%s*\n\nТеперь отправьте фотографии (от 1 до 5)", state.Answer))
+		h.saveAddDraft(ctx, msg.From.ID)
+
+		// Колоды предложению выбирает администратор при модерации
+		var decks []domain.Deck
+		if !state.Suggest {
+			var err error
+			decks, err = h.decks.List(ctx)
+			if err != nil {
+				log.Printf("Error listing decks: %v", err)
+			}
+		}
+
+		text := "Выберите сложность и отправьте фотографии (от 1 до 5, можно альбомом)"
+		if len(decks) > 0 {
+			text = "Выберите сложность, колоды (можно несколько) и отправьте фотографии (от 1 до 5, можно альбомом)"
+		}
+
+		// Автору предложения дубли не показываем: по ним можно узнать ответы из библиотеки
+		if !state.Suggest {
+			if warning := duplicateWarning(h.answerDuplicates(ctx, msg.Text)); warning != "" {
+				text = warning + "\n\n" + text
+			}
+		}
+
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("✅ Ответ сохранён: *%s*\n\n%s", msg.Text, text))
+		reply.ParseMode = "Markdown"
+		reply.ReplyMarkup = AddOptionsKeyboard(decks, selected, difficulty)
+		h.bot.Send(reply)
 		return
 	}
 
 	// Ожидаем фото
-	if msg.Photo != nil && len(msg.Photo) > 0 {
-		if len(state.Photos) >= 5 {
-			h.sendText(msg.Chat.ID, "Максимум 5 фотографий. Нажмите 'Завершить добавление'")
+	if len(msg.Photo) > 0 {
+		// Берём фото максимального размера
+		photo := msg.Photo[len(msg.Photo)-1]
+
+		if msg.MediaGroupID != "" {
+			h.addAlbumPhoto(ctx, msg.From.ID, msg.Chat.ID, msg.MediaGroupID, photo, !state.Suggest)
 			return
 		}
 
-		// Берём фото максимального размера
-		photo := msg.Photo[len(msg.Photo)-1]
-		state.Photos = append(state.Photos, photo.FileID)
+		h.addStateMu.Lock()
+		added := len(state.Photos) < domain.MaxPhotosPerSituation
+		if added {
+			state.addPhoto(photo)
+		}
+		count := len(state.Photos)
+		h.addStateMu.Unlock()
+
+		if !added {
+			h.sendText(msg.Chat.ID, fmt.Sprintf("Максимум %d фотографий. Нажмите 'Завершить добавление'", domain.MaxPhotosPerSituation))
+			return
+		}
+		h.saveAddDraft(ctx, msg.From.ID)
 
-		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("📷 Фото %d добавлено\n\nМожете отправить ещё или нажмите кнопку для завершения", len(state.Photos)))
+		text := fmt.Sprintf("📷 Фото %d добавлено", count)
+		if !state.Suggest {
+			if warning := duplicateWarning(h.photoDuplicates(ctx, photo, count)); warning != "" {
+				text += "\n\n" + warning
+			}
+		}
+
+		reply := tgbotapi.NewMessage(msg.Chat.ID, text+"\n\nМожете отправить ещё или нажмите кнопку для завершения")
 		reply.ReplyMarkup = AddPhotoKeyboard()
 		h.bot.Send(reply)
 	}
 }
 
 func (h *Handler) cmdStart(ctx context.Context, msg *tgbotapi.Message) {
-	photo, err := h.game.StartNewRound(ctx)
+	key := h.game.KeyForChat(msg.Chat.ID)
+
+	// /start фильмы, офис — играть только ситуациями из этих колод; /start все — из всех
+	if args := strings.TrimSpace(msg.CommandArguments()); args != "" {
+		if _, attached := h.game.AttachedSession(msg.Chat.ID); attached {
+			h.sendText(msg.Chat.ID, "Колоды веб-комнаты выбираются при её создании")
+		} else if !h.selectDecks(ctx, msg.Chat.ID, key, args) {
+			return
+		}
+	}
+
+	// В привязанной комнате продолжаем уже идущий раунд, чтобы не сбить игру на экране
+	photo, err := h.game.GetCurrentPhoto(key)
+	if _, attached := h.game.AttachedSession(msg.Chat.ID); !attached || err != nil {
+		photo, err = h.game.StartNewRound(ctx, key)
+	}
 	if err != nil {
 		if err == service.ErrNoSituations {
 			h.sendText(msg.Chat.ID, "😔 Нет доступных ситуаций. Попросите администратора добавить новые или сбросить игру командой /reset")
@@ -298,32 +624,75 @@ func (h *Handler) cmdStart(ctx context.Context, msg *tgbotapi.Message) {
 		return
 	}
 
-	current, total, _ := h.game.GetCurrentPhotoInfo()
+	h.sendGamePhoto(msg.Chat.ID, key, photo)
+}
+
+func (h *Handler) cmdJoin(ctx context.Context, msg *tgbotapi.Message) {
+	code := strings.TrimSpace(msg.CommandArguments())
+	if code == "" {
+		h.sendText(msg.Chat.ID, "Укажите код комнаты: `/join КОД`\n\nКод показан в шапке веб-интерфейса")
+		return
+	}
+
+	session, err := h.game.AttachChat(msg.Chat.ID, code)
+	if err != nil {
+		h.sendText(msg.Chat.ID, "❌ Комната не найдена")
+		return
+	}
+
+	h.sendText(msg.Chat.ID, fmt.Sprintf("🔗 Чат подключён к комнате *%s*\n\nКнопки игры теперь управляют игрой на экране. /start — показать текущую ситуацию, /leave — отключиться", session.Code))
+}
+
+func (h *Handler) cmdLeave(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.game.DetachChat(msg.Chat.ID) {
+		h.sendText(msg.Chat.ID, "Чат не подключён к комнате")
+		return
+	}
 
-	photoMsg := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileID(photo.FileID))
-	photoMsg.Caption = fmt.Sprintf("🎯 Угадайте, что это?\n\nФото %d из %d", current, total)
-	photoMsg.ReplyMarkup = GameKeyboard(current < total)
-	h.bot.Send(photoMsg)
+	h.sendText(msg.Chat.ID, "🔌 Чат отключён от комнаты")
 }
 
 func (h *Handler) cmdAdd(ctx context.Context, msg *tgbotapi.Message) {
-	if !h.isAdmin(msg.From.ID) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
 		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
 		return
 	}
 
+	// Незавершённый черновик (в том числе сохранённый до перезапуска) предлагаем продолжить
+	if draft := h.addDraft(ctx, msg.From.ID); draft != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("📝 У вас есть незавершённый черновик: %s\n\nПродолжить его или начать заново?", draftSummary(draft)))
+		reply.ParseMode = "Markdown"
+		reply.ReplyMarkup = ResumeAddKeyboard(false)
+		h.bot.Send(reply)
+		return
+	}
+
+	h.startAdd(ctx, msg.From.ID, msg.Chat.ID, false)
+}
+
+// startAdd начинает новый черновик /add или, если suggest, предложения /suggest
+func (h *Handler) startAdd(ctx context.Context, userID, chatID int64, suggest bool) {
 	h.addStateMu.Lock()
-	h.addState[msg.From.ID] = &AddSituationState{Waiting: true}
+	if old, ok := h.addState[userID]; ok {
+		old.album.stop()
+	}
+	h.addState[userID] = &AddSituationState{Difficulty: domain.DifficultyMedium, Suggest: suggest, ChatID: chatID, Waiting: true, UpdatedAt: time.Now()}
 	h.addStateMu.Unlock()
+	h.drafts.Delete(ctx, userID, domain.DraftAdd)
+
+	title := "📝 *Добавление новой ситуации*"
+	if suggest {
+		title = "💡 *Предложить ситуацию*\n\nПосле проверки администратором она попадёт в игру"
+	}
 
-	reply := tgbotapi.NewMessage(msg.Chat.ID, "📝 *Добавление новой ситуации*\n\nВведите правильный ответ (что изображено на фото):")
+	reply := tgbotapi.NewMessage(chatID, title+"\n\nВведите правильный ответ (что изображено на фото):")
 	reply.ParseMode = "Markdown"
 	reply.ReplyMarkup = CancelAddKeyboard()
 	h.bot.Send(reply)
 }
 
 func (h *Handler) cmdReset(ctx context.Context, msg *tgbotapi.Message) {
-	if !h.isAdmin(msg.From.ID) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
 		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
 		return
 	}
@@ -334,12 +703,12 @@ func (h *Handler) cmdReset(ctx context.Context, msg *tgbotapi.Message) {
 }
 
 func (h *Handler) cmdDelete(ctx context.Context, msg *tgbotapi.Message) {
-	if !h.isAdmin(msg.From.ID) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
 		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
 		return
 	}
 
-	total, _, err := h.repo.GetStats(ctx)
+	total, _, err := h.repo.GetStats(ctx, domain.SituationFilter{})
 	if err != nil {
 		log.Printf("Error getting stats: %v", err)
 		h.sendText(msg.Chat.ID, "Ошибка получения статистики")
@@ -358,7 +727,9 @@ func (h *Handler) cmdDelete(ctx context.Context, msg *tgbotapi.Message) {
 }
 
 func (h *Handler) cmdStats(ctx context.Context, msg *tgbotapi.Message) {
-	total, used, remaining, err := h.game.GetStats(ctx)
+	filter := h.game.Filter(h.game.KeyForChat(msg.Chat.ID))
+
+	total, used, remaining, err := h.game.GetStats(ctx, filter)
 	if err != nil {
 		log.Printf("Error getting stats: %v", err)
 		h.sendText(msg.Chat.ID, "Ошибка получения статистики")
@@ -366,9 +737,11 @@ func (h *Handler) cmdStats(ctx context.Context, msg *tgbotapi.Message) {
 	}
 
 	text := fmt.Sprintf("📊 *Статистика игры*\n\n"+
+		"Колоды: %s\n"+
+		"Сложность: %s\n"+
 		"Всего ситуаций: %d\n"+
 		"Сыграно: %d\n"+
-		"Осталось: %d", total, used, remaining)
+		"Осталось: %d", h.deckNames(ctx, filter.DeckIDs, "все"), filterDifficultyLabel(filter), total, used, remaining)
 
 	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
 	reply.ParseMode = "Markdown"
@@ -380,23 +753,50 @@ func (h *Handler) cmdHelp(ctx context.Context, msg *tgbotapi.Message) {
 
 *Команды игры:*
 /start — начать игру (показать ситуацию)
+/suggest — предложить свою ситуацию (в личном чате с ботом)
+/start КОЛОДА, КОЛОДА — играть только ситуациями из этих колод (/start все — из всех)
+/decks — список колод
+/difficulty лёгкие, средние, сложные — играть только ситуациями этой сложности (/difficulty чередовать — по очереди, /difficulty все — любые)
 /stats — статистика игры
+/timer СЕКУНДЫ — само открывать следующее фото и ответ через столько секунд (/timer выкл — без таймера)
+/guess вкл|выкл — проверять ответы, написанные в чат (в группах включено сразу)
+/top — рейтинг угадавших в этом чате (/top сброс — обнулить, для судьи)
+/join КОД — управлять игрой веб-комнаты из этого чата
+/leave — отключить чат от веб-комнаты
 
 *Команды администратора:*
 /add — добавить новую ситуацию
+/suggestions — предложенные игроками ситуации, ждущие проверки
+/import — массово добавить ситуации из ZIP-архива
+/export — выгрузить все ситуации с фото в ZIP-архив
+/web — ссылка на админ-панель в браузере
+/list — список ситуаций
+/show ID — показать ситуацию и отредактировать её
+/newdeck НАЗВАНИЕ | ОПИСАНИЕ — создать колоду
 /reset — сбросить игру (все ситуации снова доступны)
 /delete — удалить ВСЕ ситуации и фото
 
+*Команды владельца:*
+/grant ID РОЛЬ — выдать роль: администратор, судья или игрок
+/revoke ID — снять роль
+
 *Как играть:*
 1. Нажмите /start
 2. Смотрите на фото и угадывайте ситуацию
 3. Кнопка "Ещё" покажет фото с другого ракурса
-4. "Правильный ответ" покажет ответ
-5. "Следующий ход" — переход к новой ситуации
+4. "Подсказка" откроет текстовую подсказку, если она есть, — но очки за ход уменьшатся
+5. "Правильный ответ" покажет ответ
+6. "Следующий ход" — переход к новой ситуации
+
+*Режим угадывания:*
+✍️ В групповом чате просто пишите ответы: первый, кто угадает, получит BazuCoin, и бот сразу покажет следующую ситуацию
 
 *BazuCoin:*
 🤑 За каждый ход можно получить от 0 до 3 BazuCoin
-Возможные значения: 0, 0.5, 1, 1.5, 2, 2.5, 3`
+Возможные значения: 0, 0.5, 1, 1.5, 2, 2.5, 3
+Очки умножаются на сложность ситуации: лёгкая ×1, средняя ×1.5, сложная ×2
+Каждая открытая подсказка вычитает 0.5 BazuCoin до умножения
+Если у веб-комнаты есть правило очков по числу фото, судье достаточно нажать «✅ Угадал»`
 
 	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
 	reply.ParseMode = "Markdown"
@@ -405,7 +805,9 @@ func (h *Handler) cmdHelp(ctx context.Context, msg *tgbotapi.Message) {
 
 // Callback handlers
 func (h *Handler) cbMorePhoto(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	photo, err := h.game.NextPhoto(ctx)
+	key := h.game.KeyForChat(cb.Message.Chat.ID)
+
+	photo, err := h.game.NextPhoto(ctx, key)
 	if err != nil {
 		if err == service.ErrNoMorePhotos {
 			h.sendText(cb.Message.Chat.ID, "Больше нет фотографий для этой ситуации")
@@ -415,16 +817,13 @@ func (h *Handler) cbMorePhoto(ctx context.Context, cb *tgbotapi.CallbackQuery) {
 		return
 	}
 
-	current, total, _ := h.game.GetCurrentPhotoInfo()
-
-	photoMsg := tgbotapi.NewPhoto(cb.Message.Chat.ID, tgbotapi.FileID(photo.FileID))
-	photoMsg.Caption = fmt.Sprintf("🎯 Угадайте, что это?\n\nФото %d из %d", current, total)
-	photoMsg.ReplyMarkup = GameKeyboard(current < total)
-	h.bot.Send(photoMsg)
+	h.sendGamePhoto(cb.Message.Chat.ID, key, photo)
 }
 
 func (h *Handler) cbShowAnswer(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	answer, err := h.game.GetAnswer(ctx)
+	key := h.game.KeyForChat(cb.Message.Chat.ID)
+
+	answer, err := h.game.ShowAnswer(ctx, key)
 	if err != nil {
 		log.Printf("Error getting answer: %v", err)
 		return
@@ -433,70 +832,150 @@ func (h *Handler) cbShowAnswer(ctx context.Context, cb *tgbotapi.CallbackQuery)
 	h.sendText(cb.Message.Chat.ID, fmt.Sprintf("✅ Правильный ответ:\n\n*%s*", answer))
 }
 
-func (h *Handler) cbNextTurn(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	// Завершаем текущий раунд
-	if err := h.game.FinishRound(ctx); err != nil {
-		log.Printf("Error finishing round: %v", err)
+// cbShowHint открывает следующую подсказку раунда: она уменьшает BazuCoin за ход
+func (h *Handler) cbShowHint(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	key := h.game.KeyForChat(cb.Message.Chat.ID)
+
+	hint, err := h.game.ShowHint(key)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoMoreHints):
+			h.sendText(cb.Message.Chat.ID, "Подсказок к этой ситуации больше нет")
+		case errors.Is(err, service.ErrAnswerShown):
+			h.sendText(cb.Message.Chat.ID, "Ответ уже открыт")
+		default:
+			log.Printf("Error showing hint: %v", err)
+		}
+		return
+	}
+
+	h.sendText(cb.Message.Chat.ID, fmt.Sprintf("💡 Подсказка %d из %d:\n\n*%s*\n\nОчки за ход: −%g",
+		hint.Shown, hint.Total, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, hint.Text), float64(hint.Shown)*service.HintPenalty))
+
+	if hint.Shown == hint.Total {
+		h.hideHintButton(cb.Message.Chat.ID, cb.Message.MessageID, key)
 	}
+}
+
+func (h *Handler) cbNextTurn(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	h.nextTurn(ctx, cb.Message.Chat.ID)
+}
 
-	// Начинаем новый
-	photo, err := h.game.StartNewRound(ctx)
+// nextTurn завершает текущий раунд игры чата и показывает новую ситуацию
+func (h *Handler) nextTurn(ctx context.Context, chatID int64) {
+	key := h.game.KeyForChat(chatID)
+
+	photo, err := h.game.NextTurn(ctx, key)
 	if err != nil {
 		if err == service.ErrNoSituations {
-			h.sendText(cb.Message.Chat.ID, "🎉 Все ситуации сыграны! Используйте /reset для новой игры")
+			h.sendText(chatID, "🎉 Все ситуации сыграны! Используйте /reset для новой игры")
+			h.finishAttachedSession(ctx, chatID)
 			return
 		}
 		log.Printf("Error starting new round: %v", err)
 		return
 	}
 
-	current, total, _ := h.game.GetCurrentPhotoInfo()
+	h.sendGamePhoto(chatID, key, photo)
+}
 
-	photoMsg := tgbotapi.NewPhoto(cb.Message.Chat.ID, tgbotapi.FileID(photo.FileID))
-	photoMsg.Caption = fmt.Sprintf("🎯 Угадайте, что это?\n\nФото %d из %d", current, total)
-	photoMsg.ReplyMarkup = GameKeyboard(current < total)
-	h.bot.Send(photoMsg)
+// finishAttachedSession завершает веб-комнату, к которой привязан чат, и присылает итоги
+func (h *Handler) finishAttachedSession(ctx context.Context, chatID int64) {
+	code, ok := h.game.AttachedSession(chatID)
+	if !ok {
+		return
+	}
+
+	scoreboard, err := h.game.FinishGame(ctx, code)
+	if err != nil {
+		log.Printf("Error finishing session: %v", err)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🏆 *Итоги игры*\n\n")
+	for i, p := range scoreboard {
+		sb.WriteString(fmt.Sprintf("%d. %s — %g 🤑\n", i+1, p.Name, p.Score))
+		if len(p.Members) > 0 {
+			sb.WriteString("    " + strings.Join(p.Members, ", ") + "\n")
+		}
+	}
+	h.sendText(chatID, sb.String())
 }
 
 func (h *Handler) cbFinishAdd(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	if !h.isAdmin(cb.From.ID) {
+	if !h.canAdd(ctx, cb.From.ID) {
 		return
 	}
 
-	h.addStateMu.RLock()
+	// Забираем черновик под блокировкой, чтобы повторное нажатие или повтор callback от Telegram
+	// не сохранили ситуацию дважды; если сохранить не удастся, черновик вернётся.
+	// Альбом, который ещё догружается, войдёт в ситуацию тем, что уже пришло.
+	h.addStateMu.Lock()
 	state, exists := h.addState[cb.From.ID]
-	h.addStateMu.RUnlock()
+	ready := exists && state.Answer != "" && len(state.Photos) > 0
+	if ready {
+		state.album.stop()
+		state.album = nil
+		delete(h.addState, cb.From.ID)
+		h.drafts.Delete(ctx, cb.From.ID, domain.DraftAdd)
+	}
+	h.addStateMu.Unlock()
 
-	if !exists || state.Answer == "" || len(state.Photos) == 0 {
-		h.sendText(cb.Message.Chat.ID, "❌ Нужно указать ответ и добавить хотя бы одно фото")
+	if !ready {
+		if exists {
+			h.sendText(cb.Message.Chat.ID, "❌ Нужно указать ответ и добавить хотя бы одно фото")
+		}
 		return
 	}
 
-	// Сохраняем в базу
-	err := h.repo.Create(ctx, state.Answer, state.Photos)
+	if state.Suggest {
+		if !h.submitSuggestion(ctx, cb, state) {
+			h.restoreAddState(ctx, cb.From.ID, state)
+		}
+		return
+	}
+
+	// Сохраняем в базу вместе с колодами: либо всё, либо ничего
+	ids, err := h.repo.CreateBatch(ctx, []domain.NewSituation{{
+		Answer:       state.Answer,
+		Difficulty:   state.Difficulty,
+		DeckIDs:      state.DeckIDs,
+		PhotoFileIDs: state.Photos,
+	}})
 	if err != nil {
 		log.Printf("Error saving situation: %v", err)
+		h.restoreAddState(ctx, cb.From.ID, state)
 		h.sendText(cb.Message.Chat.ID, "Ошибка сохранения. Попробуйте ещё раз.")
 		return
 	}
+	situationID := ids[0]
 
-	h.addStateMu.Lock()
-	delete(h.addState, cb.From.ID)
-	h.addStateMu.Unlock()
+	h.savePhotoUniqueIDs(ctx, situationID, state.UniqueIDs)
+
+	// Скачиваем фото в локальное хранилище; если не вышло, они докачаются при первом показе
+	if err := h.photos.StoreSituationPhotos(ctx, situationID); err != nil {
+		log.Printf("Error storing photos of situation %d: %v", situationID, err)
+	}
 
-	h.sendText(cb.Message.Chat.ID, fmt.Sprintf("✅ Ситуация добавлена!\n\nОтвет: %s\nФотографий: %d", state.Answer, len(state.Photos)))
+	h.sendText(cb.Message.Chat.ID, fmt.Sprintf("✅ Ситуация добавлена!\n\nОтвет: %s\nСложность: %s\nФотографий: %d\nКолоды: %s",
+		state.Answer, difficultyLabel(state.Difficulty), len(state.Photos), h.deckNames(ctx, state.DeckIDs, "без колоды")))
 }
 
 func (h *Handler) cbCancelAdd(ctx context.Context, cb *tgbotapi.CallbackQuery) {
 	h.addStateMu.Lock()
+	if state, ok := h.addState[cb.From.ID]; ok {
+		state.album.stop()
+	}
 	delete(h.addState, cb.From.ID)
 	h.addStateMu.Unlock()
+	h.drafts.Delete(ctx, cb.From.ID, domain.DraftAdd)
 
 	h.sendText(cb.Message.Chat.ID, "❌ Добавление отменено")
 }
 
 func (h *Handler) cbConfirmReset(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	if !h.isAdmin(cb.From.ID) {
+	if !h.can(ctx, cb.From.ID, domain.PermManageContent) {
 		return
 	}
 
@@ -514,11 +993,11 @@ func (h *Handler) cbCancelReset(ctx context.Context, cb *tgbotapi.CallbackQuery)
 }
 
 func (h *Handler) cbConfirmDelete(ctx context.Context, cb *tgbotapi.CallbackQuery) {
-	if !h.isAdmin(cb.From.ID) {
+	if !h.can(ctx, cb.From.ID, domain.PermManageContent) {
 		return
 	}
 
-	count, err := h.repo.DeleteAll(ctx)
+	count, err := h.photos.DeleteAll(ctx)
 	if err != nil {
 		log.Printf("Error deleting all: %v", err)
 		h.sendText(cb.Message.Chat.ID, "Ошибка удаления данных")
@@ -534,12 +1013,49 @@ func (h *Handler) cbCancelDelete(ctx context.Context, cb *tgbotapi.CallbackQuery
 	h.sendText(cb.Message.Chat.ID, "❌ Удаление отменено")
 }
 
+func (h *Handler) sendGamePhoto(chatID int64, key string, photo *domain.Photo) {
+	photoMsg := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(photo.FileID))
+
+	snapshot, err := h.game.Snapshot(key)
+	if err != nil {
+		photoMsg.Caption = "🎯 Угадайте, что это?"
+		h.bot.Send(photoMsg)
+		return
+	}
+
+	current, total := len(snapshot.Photos), snapshot.TotalPhotos
+	caption := fmt.Sprintf("🎯 Угадайте, что это?\n\nФото %d из %d\nСложность: %s", current, total, difficultyLabel(snapshot.Difficulty))
+	photoMsg.Caption = caption
+	if !snapshot.Deadline.IsZero() {
+		photoMsg.Caption += countdownLine(snapshot.Deadline, current < total)
+	}
+	photoMsg.ReplyMarkup = GameKeyboard(current < total, len(snapshot.Hints) < snapshot.TotalHints)
+
+	sent, err := h.bot.Send(photoMsg)
+	if err != nil {
+		log.Printf("Error sending game photo: %v", err)
+		return
+	}
+	h.rememberGameMessage(chatID, sent.MessageID, key, snapshot, caption)
+}
+
 func (h *Handler) sendText(chatID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "Markdown"
 	h.bot.Send(msg)
 }
 
-func (h *Handler) isAdmin(userID int64) bool {
-	return userID == h.adminID
+// canAdd проверяет право вести свой черновик: предложить ситуацию (/suggest) может любой,
+// добавить её сразу в библиотеку (/add) — только администратор
+func (h *Handler) canAdd(ctx context.Context, userID int64) bool {
+	h.addStateMu.RLock()
+	state, ok := h.addState[userID]
+	h.addStateMu.RUnlock()
+
+	return ok && state.Suggest || h.can(ctx, userID, domain.PermManageContent)
+}
+
+// can проверяет право пользователя на действие по его роли
+func (h *Handler) can(ctx context.Context, userID int64, p domain.Permission) bool {
+	return h.users.Can(ctx, userID, p)
 }
\ No newline at end of file
@@ -1,22 +1,34 @@
 package bot
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	"fmt"
+	"slices"
+	"strconv"
 
-// GameKeyboard — клавиатура во время игры
-func GameKeyboard(hasMorePhotos bool) tgbotapi.InlineKeyboardMarkup {
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// GameKeyboard — клавиатура во время игры; кнопка подсказки есть, пока остались неоткрытые подсказки
+func GameKeyboard(hasMorePhotos, hasMoreHints bool) tgbotapi.InlineKeyboardMarkup {
 	moreBtn := tgbotapi.NewInlineKeyboardButtonData("📷 Ещё", "more_photo")
 	if !hasMorePhotos {
 		moreBtn = tgbotapi.NewInlineKeyboardButtonData("📷 Ещё (нет)", "no_more")
 	}
 
+	next := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("➡️ Следующий ход", "next_turn"),
+	)
+	if hasMoreHints {
+		next = append([]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonData("💡 Подсказка", "show_hint")}, next...)
+	}
+
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			moreBtn,
 			tgbotapi.NewInlineKeyboardButtonData("✅ Правильный ответ", "show_answer"),
 		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➡️ Следующий ход", "next_turn"),
-		),
+		next,
 	)
 }
 
@@ -39,6 +51,153 @@ func CancelAddKeyboard() tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
+// ResumeAddKeyboard — продолжить незавершённый черновик или начать заново /add, а если suggest — /suggest
+func ResumeAddKeyboard(suggest bool) tgbotapi.InlineKeyboardMarkup {
+	discard := "add_discard"
+	if suggest {
+		discard = "add_discard_suggest"
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("▶️ Продолжить", "add_resume"),
+			tgbotapi.NewInlineKeyboardButtonData("🗑 Начать заново", discard),
+		),
+	)
+}
+
+// AddOptionsKeyboard — выбор сложности и колод для новой ситуации; выбранное отмечено галочкой
+func AddOptionsKeyboard(decks []domain.Deck, selected []int, difficulty int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	var levels []tgbotapi.InlineKeyboardButton
+	for _, level := range domain.Difficulties {
+		label := difficultyLabel(level)
+		if level == difficulty {
+			label = "✅ " + label
+		}
+		levels = append(levels, tgbotapi.NewInlineKeyboardButtonData(label, "add_diff_"+strconv.Itoa(level)))
+	}
+	rows = append(rows, levels)
+
+	var row []tgbotapi.InlineKeyboardButton
+	for _, d := range decks {
+		label := d.Name
+		if slices.Contains(selected, d.ID) {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "add_deck_"+strconv.Itoa(d.ID)))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "cancel_add"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// SituationListKeyboard — кнопки ситуаций страницы /list и переход между страницами
+func SituationListKeyboard(situations []domain.SituationSummary, page, pages int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, s := range situations {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("#"+strconv.Itoa(s.ID), "sit_show_"+strconv.Itoa(s.ID)))
+		if len(row) == 5 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "sit_page_"+strconv.Itoa(page-1)))
+	}
+	if page < pages-1 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("Вперёд ➡️", "sit_page_"+strconv.Itoa(page+1)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// SituationEditKeyboard — действия над ситуацией в /show
+func SituationEditKeyboard(situation *domain.SituationWithPhotos) tgbotapi.InlineKeyboardMarkup {
+	id := strconv.Itoa(situation.Situation.ID)
+
+	usedLabel := "✔️ Отметить сыгранной"
+	if situation.Situation.IsUsed {
+		usedLabel = "🆕 Вернуть в игру"
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Ответ", "sit_answer_"+id),
+			tgbotapi.NewInlineKeyboardButtonData("🔤 Синонимы", "sit_aliases_"+id),
+			tgbotapi.NewInlineKeyboardButtonData("💡 Подсказки", "sit_hints_"+id),
+			tgbotapi.NewInlineKeyboardButtonData("➕ Фото", "sit_photos_"+id),
+		),
+	}
+
+	var photos []tgbotapi.InlineKeyboardButton
+	for i, p := range situation.Photos {
+		photos = append(photos, tgbotapi.NewInlineKeyboardButtonData("➖ Фото "+strconv.Itoa(i+1), "sit_delphoto_"+strconv.Itoa(p.ID)))
+	}
+	if len(photos) > 0 {
+		rows = append(rows, photos)
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(usedLabel, "sit_used_"+id)),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🗑️ Удалить ситуацию", "sit_delete_"+id)),
+	)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// EditDoneKeyboard — завершение редактирования ситуации
+func EditDoneKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Готово", "sit_done"),
+		),
+	)
+}
+
+// ConfirmDeleteSituationKeyboard — подтверждение удаления одной ситуации
+func ConfirmDeleteSituationKeyboard(situationID int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚠️ Да, удалить", "sit_delyes_"+strconv.Itoa(situationID)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "sit_done"),
+		),
+	)
+}
+
+// SubmissionKeyboard — модерация предложенной игроком ситуации
+func SubmissionKeyboard(submissionID int) tgbotapi.InlineKeyboardMarkup {
+	id := strconv.Itoa(submissionID)
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Одобрить", "sub_ok_"+id),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", "sub_no_"+id),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Исправить ответ", "sub_edit_"+id),
+		),
+	)
+}
+
 // ConfirmResetKeyboard — клавиатура подтверждения сброса
 func ConfirmResetKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
@@ -59,22 +218,34 @@ func ConfirmDeleteKeyboard() tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
-// ScoreKeyboard — клавиатура для быстрого ввода BazuCoin
-func ScoreKeyboard() tgbotapi.InlineKeyboardMarkup {
-	return tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("0", "score_0"),
-			tgbotapi.NewInlineKeyboardButtonData("0.5", "score_0.5"),
-			tgbotapi.NewInlineKeyboardButtonData("1", "score_1"),
-			tgbotapi.NewInlineKeyboardButtonData("1.5", "score_1.5"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("2", "score_2"),
-			tgbotapi.NewInlineKeyboardButtonData("2.5", "score_2.5"),
-			tgbotapi.NewInlineKeyboardButtonData("3", "score_3"),
-		),
+// ScoreKeyboard — клавиатура для быстрого ввода BazuCoin в комнате sessionCode;
+// suggested — BazuCoin по правилу комнаты, их кнопка выделена
+func ScoreKeyboard(sessionCode string, suggested *float64) tgbotapi.InlineKeyboardMarkup {
+	data := func(score float64) string {
+		return "score_" + sessionCode + "_" + strconv.FormatFloat(score, 'f', -1, 64)
+	}
+	btn := func(score float64) tgbotapi.InlineKeyboardButton {
+		label := strconv.FormatFloat(score, 'f', -1, 64)
+		if suggested != nil && *suggested == score {
+			label = "👉 " + label
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(label, data(score))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	// С правилом очков комнаты судье достаточно отметить, угадал ли игрок
+	if suggested != nil {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ Угадал (%g)", *suggested), data(*suggested)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Не угадал (0)", data(0)),
+		))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(btn(0), btn(0.5), btn(1), btn(1.5)),
+		tgbotapi.NewInlineKeyboardRow(btn(2), btn(2.5), btn(3)),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "score_cancel"),
 		),
 	)
-}
\ No newline at end of file
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
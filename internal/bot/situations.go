@@ -0,0 +1,409 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// situationsPerPage — сколько ситуаций показывает одна страница /list
+const situationsPerPage = 10
+
+func (h *Handler) cmdList(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	page, _ := strconv.Atoi(strings.TrimSpace(msg.CommandArguments()))
+	h.sendSituationList(ctx, msg.Chat.ID, 0, max(page-1, 0))
+}
+
+func (h *Handler) cmdShow(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(msg.CommandArguments()), "#"))
+	if err != nil {
+		h.sendText(msg.Chat.ID, "Укажите номер ситуации: `/show 12`\n\nНомера есть в /list")
+		return
+	}
+
+	h.showSituation(ctx, msg.Chat.ID, id, true)
+}
+
+// sendSituationList показывает страницу списка ситуаций; если messageID не 0 — редактирует это сообщение
+func (h *Handler) sendSituationList(ctx context.Context, chatID int64, messageID int, page int) {
+	situations, total, err := h.repo.List(ctx, page*situationsPerPage, situationsPerPage)
+	if err != nil {
+		log.Printf("Error listing situations: %v", err)
+		h.sendText(chatID, "Ошибка получения списка ситуаций")
+		return
+	}
+
+	if total == 0 {
+		h.sendText(chatID, "Ситуаций пока нет. Добавьте первую через /add")
+		return
+	}
+
+	pages := (total + situationsPerPage - 1) / situationsPerPage
+	if page >= pages {
+		page = pages - 1
+		situations, _, err = h.repo.List(ctx, page*situationsPerPage, situationsPerPage)
+		if err != nil {
+			log.Printf("Error listing situations: %v", err)
+			h.sendText(chatID, "Ошибка получения списка ситуаций")
+			return
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🗂 *Ситуации* (всего %d, стр. %d из %d)\n\n", total, page+1, pages))
+	for _, s := range situations {
+		status := "🆕"
+		if s.IsUsed {
+			status = "✔️"
+		}
+		sb.WriteString(fmt.Sprintf("%s #%d %s — %d 📷, %s\n", status, s.ID, s.Answer, s.PhotoCount, difficultyLabel(s.Difficulty)))
+	}
+	sb.WriteString("\n🆕 — ещё не сыграна, ✔️ — сыграна")
+
+	keyboard := SituationListKeyboard(situations, page, pages)
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, sb.String(), keyboard)
+		edit.ParseMode = "Markdown"
+		h.bot.Send(edit)
+		return
+	}
+
+	reply := tgbotapi.NewMessage(chatID, sb.String())
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = keyboard
+	h.bot.Send(reply)
+}
+
+// showSituation присылает карточку ситуации с кнопками редактирования; withPhotos — показать и фото альбомом
+func (h *Handler) showSituation(ctx context.Context, chatID int64, id int, withPhotos bool) {
+	situation, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.sendText(chatID, fmt.Sprintf("❌ Ситуация #%d не найдена", id))
+			return
+		}
+		log.Printf("Error getting situation %d: %v", id, err)
+		h.sendText(chatID, "Ошибка получения ситуации")
+		return
+	}
+
+	if withPhotos && len(situation.Photos) > 0 {
+		media := make([]interface{}, len(situation.Photos))
+		for i, p := range situation.Photos {
+			photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileID(p.FileID))
+			if i == 0 {
+				photo.Caption = fmt.Sprintf("Ситуация #%d", id)
+			}
+			media[i] = photo
+		}
+		if _, err := h.bot.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media)); err != nil {
+			log.Printf("Error sending photos of situation %d: %v", id, err)
+		}
+	}
+
+	status := "ещё не сыграна"
+	if situation.Situation.IsUsed {
+		status = "сыграна"
+	}
+
+	aliases := "нет"
+	if len(situation.Situation.Aliases) > 0 {
+		aliases = tgbotapi.EscapeText(tgbotapi.ModeMarkdown, strings.Join(situation.Situation.Aliases, "; "))
+	}
+
+	hints := "нет"
+	if len(situation.Situation.Hints) > 0 {
+		var sb strings.Builder
+		for i, hint := range situation.Situation.Hints {
+			sb.WriteString(fmt.Sprintf("\n%d. %s", i+1, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, hint)))
+		}
+		hints = sb.String()
+	}
+
+	text := fmt.Sprintf("🗂 *Ситуация #%d*\n\n"+
+		"Ответ: *%s*\n"+
+		"Синонимы: %s\n"+
+		"Подсказки: %s\n"+
+		"Сложность: %s\n"+
+		"Фотографий: %d\n"+
+		"Статус: %s",
+		id, situation.Situation.Answer, aliases, hints, difficultyLabel(situation.Situation.Difficulty), len(situation.Photos), status)
+
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = SituationEditKeyboard(situation)
+	h.bot.Send(reply)
+}
+
+// cbSituation обрабатывает кнопки списка и карточки ситуации (sit_<действие>_<ID>)
+func (h *Handler) cbSituation(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.can(ctx, cb.From.ID, domain.PermManageContent) {
+		return
+	}
+
+	chatID := cb.Message.Chat.ID
+
+	if cb.Data == "sit_done" {
+		h.clearEditState(cb.From.ID)
+		h.sendText(chatID, "👌 Готово")
+		return
+	}
+
+	action, arg, ok := strings.Cut(strings.TrimPrefix(cb.Data, "sit_"), "_")
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return
+	}
+
+	switch action {
+	case "page":
+		h.sendSituationList(ctx, chatID, cb.Message.MessageID, id)
+
+	case "show":
+		h.showSituation(ctx, chatID, id, true)
+
+	case "answer":
+		h.setEditState(cb.From.ID, &EditSituationState{SituationID: id, Field: "answer", ChatID: chatID})
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("✏️ Введите новый ответ для ситуации #%d", id))
+		reply.ReplyMarkup = EditDoneKeyboard()
+		h.bot.Send(reply)
+
+	case "aliases":
+		h.setEditState(cb.From.ID, &EditSituationState{SituationID: id, Field: "aliases", ChatID: chatID})
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("🔤 Введите синонимы ответа для ситуации #%d — другие варианты, которые тоже засчитываются как правильные. "+
+			"Каждый вариант с новой строки, не больше %d. Отправьте «-», чтобы убрать все", id, domain.MaxAliases))
+		reply.ReplyMarkup = EditDoneKeyboard()
+		h.bot.Send(reply)
+
+	case "hints":
+		h.setEditState(cb.From.ID, &EditSituationState{SituationID: id, Field: "hints", ChatID: chatID})
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("💡 Введите подсказки для ситуации #%d в том порядке, в котором их открывать. "+
+			"Каждая подсказка с новой строки, не больше %d; каждая открытая в игре стоит %g BazuCoin. Отправьте «-», чтобы убрать все",
+			id, domain.MaxHints, service.HintPenalty))
+		reply.ReplyMarkup = EditDoneKeyboard()
+		h.bot.Send(reply)
+
+	case "photos":
+		h.setEditState(cb.From.ID, &EditSituationState{SituationID: id, Field: "photos", ChatID: chatID})
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("📷 Отправьте фото для ситуации #%d (всего не больше %d)", id, domain.MaxPhotosPerSituation))
+		reply.ReplyMarkup = EditDoneKeyboard()
+		h.bot.Send(reply)
+
+	case "delphoto":
+		h.deleteSituationPhoto(ctx, chatID, id)
+
+	case "used":
+		situation, err := h.repo.GetByID(ctx, id)
+		if err != nil {
+			log.Printf("Error getting situation %d: %v", id, err)
+			h.sendText(chatID, "Ошибка получения ситуации")
+			return
+		}
+		if err := h.repo.SetUsed(ctx, id, !situation.Situation.IsUsed); err != nil {
+			log.Printf("Error toggling situation %d: %v", id, err)
+			h.sendText(chatID, "Ошибка сохранения")
+			return
+		}
+		h.showSituation(ctx, chatID, id, false)
+
+	case "delete":
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("🗑️ Удалить ситуацию #%d вместе с фото?", id))
+		reply.ReplyMarkup = ConfirmDeleteSituationKeyboard(id)
+		h.bot.Send(reply)
+
+	case "delyes":
+		if err := h.photos.DeleteSituation(ctx, id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				h.sendText(chatID, fmt.Sprintf("❌ Ситуация #%d не найдена", id))
+				return
+			}
+			// Ситуация уже удалена из базы, не удалось убрать только файлы
+			log.Printf("Error deleting situation %d: %v", id, err)
+		}
+		h.clearEditState(cb.From.ID)
+		h.sendText(chatID, fmt.Sprintf("✅ Ситуация #%d удалена", id))
+	}
+}
+
+func (h *Handler) handleEditState(ctx context.Context, msg *tgbotapi.Message, state *EditSituationState) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		return
+	}
+
+	switch state.Field {
+	case "submission":
+		h.editSubmissionAnswer(ctx, msg, state)
+
+	case "answer":
+		answer := strings.TrimSpace(msg.Text)
+		if answer == "" {
+			h.sendText(msg.Chat.ID, "Пожалуйста, введите текстовый ответ")
+			return
+		}
+
+		if err := h.repo.UpdateAnswer(ctx, state.SituationID, answer); err != nil {
+			log.Printf("Error updating answer of situation %d: %v", state.SituationID, err)
+			h.sendText(msg.Chat.ID, "Ошибка сохранения ответа")
+			return
+		}
+
+		h.clearEditState(msg.From.ID)
+		h.showSituation(ctx, msg.Chat.ID, state.SituationID, false)
+
+	case "aliases":
+		text := strings.TrimSpace(msg.Text)
+		if text == "" {
+			h.sendText(msg.Chat.ID, "Пожалуйста, введите синонимы текстом")
+			return
+		}
+
+		situation, err := h.repo.GetByID(ctx, state.SituationID)
+		if err != nil {
+			log.Printf("Error getting situation %d: %v", state.SituationID, err)
+			h.sendText(msg.Chat.ID, "Ошибка получения ситуации")
+			return
+		}
+
+		var aliases []string
+		if text != "-" {
+			aliases = domain.CleanAliases(situation.Situation.Answer, strings.Split(text, "\n"))
+		}
+		if len(aliases) > domain.MaxAliases {
+			h.sendText(msg.Chat.ID, fmt.Sprintf("❌ Не больше %d синонимов", domain.MaxAliases))
+			return
+		}
+
+		if err := h.repo.SetAliases(ctx, state.SituationID, aliases); err != nil {
+			log.Printf("Error setting aliases of situation %d: %v", state.SituationID, err)
+			h.sendText(msg.Chat.ID, "Ошибка сохранения синонимов")
+			return
+		}
+
+		h.clearEditState(msg.From.ID)
+		h.showSituation(ctx, msg.Chat.ID, state.SituationID, false)
+
+	case "hints":
+		text := strings.TrimSpace(msg.Text)
+		if text == "" {
+			h.sendText(msg.Chat.ID, "Пожалуйста, введите подсказки текстом")
+			return
+		}
+
+		var hints []string
+		if text != "-" {
+			hints = domain.CleanHints(strings.Split(text, "\n"))
+		}
+		if len(hints) > domain.MaxHints {
+			h.sendText(msg.Chat.ID, fmt.Sprintf("❌ Не больше %d подсказок", domain.MaxHints))
+			return
+		}
+
+		if err := h.repo.SetHints(ctx, state.SituationID, hints); err != nil {
+			log.Printf("Error setting hints of situation %d: %v", state.SituationID, err)
+			h.sendText(msg.Chat.ID, "Ошибка сохранения подсказок")
+			return
+		}
+
+		h.clearEditState(msg.From.ID)
+		h.showSituation(ctx, msg.Chat.ID, state.SituationID, false)
+
+	case "photos":
+		if len(msg.Photo) == 0 {
+			h.sendText(msg.Chat.ID, "Отправьте фотографию или нажмите «Готово»")
+			return
+		}
+
+		count, err := h.repo.CountPhotos(ctx, state.SituationID)
+		if err != nil {
+			log.Printf("Error counting photos of situation %d: %v", state.SituationID, err)
+			h.sendText(msg.Chat.ID, "Ошибка сохранения фото")
+			return
+		}
+		if count >= domain.MaxPhotosPerSituation {
+			h.sendText(msg.Chat.ID, fmt.Sprintf("Максимум %d фотографий. Удалите лишние или нажмите «Готово»", domain.MaxPhotosPerSituation))
+			return
+		}
+
+		// Берём фото максимального размера
+		photo := msg.Photo[len(msg.Photo)-1]
+		photoID, err := h.repo.AddPhoto(ctx, state.SituationID, photo.FileID)
+		if err != nil {
+			log.Printf("Error adding photo to situation %d: %v", state.SituationID, err)
+			h.sendText(msg.Chat.ID, "Ошибка сохранения фото")
+			return
+		}
+		if err := h.repo.SetPhotoFingerprint(ctx, photoID, photo.FileUniqueID, 0); err != nil {
+			log.Printf("Error saving fingerprint of photo %d: %v", photoID, err)
+		}
+
+		// Скачиваем в локальное хранилище; если не вышло, фото докачается при первом показе
+		if err := h.photos.StoreSituationPhotos(ctx, state.SituationID); err != nil {
+			log.Printf("Error storing photos of situation %d: %v", state.SituationID, err)
+		}
+
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("📷 Фото %d добавлено к ситуации #%d", count+1, state.SituationID))
+		reply.ReplyMarkup = EditDoneKeyboard()
+		h.bot.Send(reply)
+	}
+}
+
+func (h *Handler) deleteSituationPhoto(ctx context.Context, chatID int64, photoID int) {
+	photo, err := h.repo.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.sendText(chatID, "❌ Фото уже удалено")
+			return
+		}
+		log.Printf("Error getting photo %d: %v", photoID, err)
+		h.sendText(chatID, "Ошибка удаления фото")
+		return
+	}
+
+	count, err := h.repo.CountPhotos(ctx, photo.SituationID)
+	if err == nil && count <= 1 {
+		h.sendText(chatID, "❌ Нельзя удалить последнее фото. Удалите ситуацию целиком или сначала добавьте другое фото")
+		return
+	}
+
+	if err := h.photos.DeletePhoto(ctx, photoID); err != nil {
+		log.Printf("Error deleting photo %d: %v", photoID, err)
+		if errors.Is(err, domain.ErrNotFound) {
+			h.sendText(chatID, "❌ Фото уже удалено")
+			return
+		}
+	}
+
+	h.showSituation(ctx, chatID, photo.SituationID, true)
+}
+
+func (h *Handler) setEditState(userID int64, state *EditSituationState) {
+	h.editStateMu.Lock()
+	h.editState[userID] = state
+	h.editStateMu.Unlock()
+}
+
+func (h *Handler) clearEditState(userID int64) {
+	h.editStateMu.Lock()
+	delete(h.editState, userID)
+	h.editStateMu.Unlock()
+}
@@ -0,0 +1,268 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// suggestionsPageSize — сколько предложений показывает /suggestions за раз
+const suggestionsPageSize = 5
+
+// cmdSuggest начинает предложение ситуации: тот же диалог, что /add, но ситуация
+// попадает в игру только после одобрения администратором
+func (h *Handler) cmdSuggest(ctx context.Context, msg *tgbotapi.Message) {
+	// В общем чате ответ увидят будущие игроки
+	if !msg.Chat.IsPrivate() {
+		h.sendText(msg.Chat.ID, "🔒 Предложить ситуацию можно только в личном чате с ботом, чтобы никто не увидел ответ")
+		return
+	}
+
+	if draft := h.addDraft(ctx, msg.From.ID); draft != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("📝 У вас есть незавершённый черновик: %s\n\nПродолжить его или начать заново?", draftSummary(draft)))
+		reply.ParseMode = "Markdown"
+		reply.ReplyMarkup = ResumeAddKeyboard(true)
+		h.bot.Send(reply)
+		return
+	}
+
+	h.startAdd(ctx, msg.From.ID, msg.Chat.ID, true)
+}
+
+// submitSuggestion отправляет завершённый черновик /suggest на модерацию; false — если не удалось сохранить
+func (h *Handler) submitSuggestion(ctx context.Context, cb *tgbotapi.CallbackQuery, state *AddSituationState) bool {
+	submission := &domain.Submission{
+		AuthorID:     cb.From.ID,
+		AuthorName:   displayName(cb.From),
+		Answer:       state.Answer,
+		Difficulty:   state.Difficulty,
+		PhotoFileIDs: state.Photos,
+	}
+	for _, fileID := range state.Photos {
+		submission.PhotoUniqueIDs = append(submission.PhotoUniqueIDs, state.UniqueIDs[fileID])
+	}
+
+	if err := h.submissions.Submit(ctx, submission); err != nil {
+		log.Printf("Error saving submission: %v", err)
+		h.sendText(cb.Message.Chat.ID, "Ошибка сохранения. Попробуйте ещё раз.")
+		return false
+	}
+
+	h.sendText(cb.Message.Chat.ID, fmt.Sprintf("✅ Спасибо! Предложение #%d отправлено на проверку\n\nМы напишем, когда администратор его рассмотрит", submission.ID))
+
+	h.notifyModerators(ctx, submission)
+	return true
+}
+
+// notifyModerators присылает новое предложение всем, кто может его одобрить
+func (h *Handler) notifyModerators(ctx context.Context, submission *domain.Submission) {
+	moderators, err := h.users.WithPermission(ctx, domain.PermManageContent)
+	if err != nil {
+		log.Printf("Error listing moderators: %v", err)
+		return
+	}
+
+	warning := h.submissionWarning(ctx, submission)
+	for _, moderatorID := range moderators {
+		h.sendSubmission(moderatorID, submission, warning, true)
+	}
+}
+
+// cmdSuggestions показывает предложения, ждущие модерации
+func (h *Handler) cmdSuggestions(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	pending, err := h.submissions.Pending(ctx)
+	if err != nil {
+		log.Printf("Error listing submissions: %v", err)
+		h.sendText(msg.Chat.ID, "Ошибка получения предложений")
+		return
+	}
+	if len(pending) == 0 {
+		h.sendText(msg.Chat.ID, "📭 Нет предложений, ждущих проверки")
+		return
+	}
+
+	text := fmt.Sprintf("💡 Ждут проверки: %d", len(pending))
+	if len(pending) > suggestionsPageSize {
+		text += fmt.Sprintf("\n\nПоказаны первые %d — после их проверки /suggestions покажет следующие", suggestionsPageSize)
+		pending = pending[:suggestionsPageSize]
+	}
+	h.sendText(msg.Chat.ID, text)
+
+	for i := range pending {
+		h.sendSubmission(msg.Chat.ID, &pending[i], h.submissionWarning(ctx, &pending[i]), true)
+	}
+}
+
+// cbSubmission обрабатывает кнопки модерации (sub_<действие>_<ID>)
+func (h *Handler) cbSubmission(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.can(ctx, cb.From.ID, domain.PermManageContent) {
+		return
+	}
+
+	chatID := cb.Message.Chat.ID
+
+	action, arg, ok := strings.Cut(strings.TrimPrefix(cb.Data, "sub_"), "_")
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return
+	}
+
+	switch action {
+	case "ok":
+		submission, err := h.submissions.Approve(ctx, id, cb.From.ID)
+		if err != nil {
+			h.sendSubmissionError(chatID, id, submission, err)
+			return
+		}
+		h.closeSubmissionCard(cb)
+
+		h.sendText(chatID, fmt.Sprintf("✅ Предложение #%d одобрено — теперь это ситуация #%d\n\nКолоды и остальное можно поменять: /show %d",
+			id, submission.SituationID, submission.SituationID))
+		h.sendText(submission.AuthorID, fmt.Sprintf("🎉 Ваша ситуация «%s» одобрена и добавлена в игру. Спасибо!",
+			tgbotapi.EscapeText(tgbotapi.ModeMarkdown, submission.Answer)))
+
+	case "no":
+		submission, err := h.submissions.Reject(ctx, id, cb.From.ID)
+		if err != nil {
+			h.sendSubmissionError(chatID, id, submission, err)
+			return
+		}
+		h.closeSubmissionCard(cb)
+
+		h.sendText(chatID, fmt.Sprintf("❌ Предложение #%d отклонено", id))
+		h.sendText(submission.AuthorID, fmt.Sprintf("😔 Ваша ситуация «%s» не прошла проверку. Спасибо за идею!",
+			tgbotapi.EscapeText(tgbotapi.ModeMarkdown, submission.Answer)))
+
+	case "edit":
+		submission, err := h.submissions.Get(ctx, id)
+		if err == nil && submission.Status != domain.SubmissionPending {
+			err = service.ErrSubmissionReviewed
+		}
+		if err != nil {
+			h.sendSubmissionError(chatID, id, submission, err)
+			return
+		}
+
+		h.setEditState(cb.From.ID, &EditSituationState{SubmissionID: id, Field: "submission", ChatID: cb.Message.Chat.ID})
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("✏️ Введите исправленный ответ для предложения #%d", id))
+		reply.ReplyMarkup = EditDoneKeyboard()
+		h.bot.Send(reply)
+	}
+}
+
+// editSubmissionAnswer сохраняет исправленный модератором ответ и показывает предложение снова
+func (h *Handler) editSubmissionAnswer(ctx context.Context, msg *tgbotapi.Message, state *EditSituationState) {
+	answer := strings.TrimSpace(msg.Text)
+	if answer == "" {
+		h.sendText(msg.Chat.ID, "Пожалуйста, введите текстовый ответ")
+		return
+	}
+
+	err := h.submissions.UpdateAnswer(ctx, state.SubmissionID, answer)
+	h.clearEditState(msg.From.ID)
+	if err != nil {
+		submission, _ := h.submissions.Get(ctx, state.SubmissionID)
+		h.sendSubmissionError(msg.Chat.ID, state.SubmissionID, submission, err)
+		return
+	}
+
+	submission, err := h.submissions.Get(ctx, state.SubmissionID)
+	if err != nil {
+		log.Printf("Error getting submission %d: %v", state.SubmissionID, err)
+		h.sendText(msg.Chat.ID, "Ошибка получения предложения")
+		return
+	}
+	h.sendSubmission(msg.Chat.ID, submission, h.submissionWarning(ctx, submission), false)
+}
+
+// sendSubmission присылает карточку предложения с кнопками модерации; withPhotos — показать и фото альбомом
+func (h *Handler) sendSubmission(chatID int64, submission *domain.Submission, warning string, withPhotos bool) {
+	if withPhotos {
+		media := make([]interface{}, len(submission.PhotoFileIDs))
+		for i, fileID := range submission.PhotoFileIDs {
+			photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileID(fileID))
+			if i == 0 {
+				photo.Caption = fmt.Sprintf("Предложение #%d", submission.ID)
+			}
+			media[i] = photo
+		}
+		if _, err := h.bot.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media)); err != nil {
+			log.Printf("Error sending photos of submission %d: %v", submission.ID, err)
+		}
+	}
+
+	text := fmt.Sprintf("💡 *Предложение #%d*\n\n"+
+		"Автор: %s\n"+
+		"Ответ: *%s*\n"+
+		"Сложность: %s\n"+
+		"Фотографий: %d",
+		submission.ID, userLabel(submission.AuthorID, submission.AuthorName),
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, submission.Answer), difficultyLabel(submission.Difficulty), len(submission.PhotoFileIDs))
+	if warning != "" {
+		text += "\n\n" + warning
+	}
+
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = SubmissionKeyboard(submission.ID)
+	h.bot.Send(reply)
+}
+
+// submissionWarning ищет в библиотеке ситуации с тем же ответом или фото, что у предложения
+func (h *Handler) submissionWarning(ctx context.Context, submission *domain.Submission) string {
+	var photos []service.PhotoFingerprint
+	for i, fileID := range submission.PhotoFileIDs {
+		var uniqueID string
+		if i < len(submission.PhotoUniqueIDs) {
+			uniqueID = submission.PhotoUniqueIDs[i]
+		}
+		fp, err := h.photos.FingerprintTelegramPhoto(ctx, fileID, uniqueID)
+		if err != nil {
+			// Без хеша проверяем хотя бы file_unique_id
+			log.Printf("Error fingerprinting photo of submission %d: %v", submission.ID, err)
+		}
+		photos = append(photos, fp)
+	}
+
+	duplicates, err := h.duplicates.Find(ctx, submission.Answer, photos)
+	if err != nil {
+		log.Printf("Error finding duplicates of submission %d: %v", submission.ID, err)
+		return ""
+	}
+	return duplicateWarning(duplicates)
+}
+
+// closeSubmissionCard убирает кнопки модерации с карточки рассмотренного предложения
+func (h *Handler) closeSubmissionCard(cb *tgbotapi.CallbackQuery) {
+	empty := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+	h.bot.Send(tgbotapi.NewEditMessageReplyMarkup(cb.Message.Chat.ID, cb.Message.MessageID, empty))
+}
+
+func (h *Handler) sendSubmissionError(chatID int64, id int, submission *domain.Submission, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		h.sendText(chatID, fmt.Sprintf("❌ Предложение #%d не найдено", id))
+	case errors.Is(err, service.ErrSubmissionReviewed) && submission != nil && submission.Status == domain.SubmissionApproved:
+		h.sendText(chatID, fmt.Sprintf("👌 Предложение #%d уже одобрено — это ситуация #%d", id, submission.SituationID))
+	case errors.Is(err, service.ErrSubmissionReviewed):
+		h.sendText(chatID, fmt.Sprintf("👌 Предложение #%d уже рассмотрено", id))
+	default:
+		log.Printf("Error moderating submission %d: %v", id, err)
+		h.sendText(chatID, "Ошибка сохранения. Попробуйте ещё раз.")
+	}
+}
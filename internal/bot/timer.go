@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// timerTick — как часто обновляется обратный отсчёт в подписи фото; чаще Telegram не даёт править сообщения
+const timerTick = 10 * time.Second
+
+// gameMessage — последнее фото раунда, отправленное в чат: его подпись показывает обратный отсчёт
+type gameMessage struct {
+	messageID int
+	key       string
+	roundID   int
+	photos    int    // сколько фото было открыто, когда его отправили
+	caption   string // подпись без строки таймера
+	hasMore   bool
+	hasHint   bool
+}
+
+// cmdTimer задаёт таймер на фото в чате: /timer 30, /timer выкл; без аргументов — показывает текущий
+func (h *Handler) cmdTimer(ctx context.Context, msg *tgbotapi.Message) {
+	if _, attached := h.game.AttachedSession(msg.Chat.ID); attached {
+		h.sendText(msg.Chat.ID, "Таймер веб-комнаты выбирается при её создании")
+		return
+	}
+
+	key := h.game.KeyForChat(msg.Chat.ID)
+
+	arg := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+	if arg == "" {
+		h.sendText(msg.Chat.ID, fmt.Sprintf("Сейчас: %s\n\nУкажите секунды на фото: `/timer 30` или `/timer выкл`", timerLabel(h.game.Timer(key))))
+		return
+	}
+
+	var d time.Duration
+	if arg != "выкл" && arg != "off" {
+		seconds, err := strconv.Atoi(strings.TrimRight(arg, " сs"))
+		if err != nil {
+			h.sendText(msg.Chat.ID, "❌ Укажите число секунд, например `/timer 30`, или `/timer выкл`")
+			return
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+
+	if err := h.game.SetTimer(key, d); err != nil {
+		h.sendText(msg.Chat.ID, "❌ "+err.Error())
+		return
+	}
+	h.sendText(msg.Chat.ID, fmt.Sprintf("⏱ Таймер: %s\n\nДействует со следующего раунда", timerLabel(d)))
+}
+
+func timerLabel(d time.Duration) string {
+	if d <= 0 {
+		return "выключен"
+	}
+	return fmt.Sprintf("%d с на фото, после последнего открывается ответ", int(d/time.Second))
+}
+
+// listenTimerEvents показывает в чатах фото и ответы, открытые таймером раунда
+func (h *Handler) listenTimerEvents() {
+	for event := range h.game.TimerChan {
+		for _, chatID := range h.game.ChatsForKey(event.Key) {
+			h.expireGameMessage(chatID, event.RoundID)
+
+			if event.Photo != nil {
+				h.sendGamePhoto(chatID, event.Key, event.Photo)
+				continue
+			}
+			h.sendText(chatID, fmt.Sprintf("⌛ Время вышло!\n\n✅ Правильный ответ:\n\n*%s*",
+				tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.Answer)))
+		}
+	}
+}
+
+// rememberGameMessage запоминает фото раунда, подпись которого будет показывать обратный отсчёт
+func (h *Handler) rememberGameMessage(chatID int64, messageID int, key string, snapshot *service.RoundSnapshot, caption string) {
+	h.gameMessagesMu.Lock()
+	defer h.gameMessagesMu.Unlock()
+
+	h.gameMessages[chatID] = gameMessage{
+		messageID: messageID,
+		key:       key,
+		roundID:   snapshot.RoundID,
+		photos:    len(snapshot.Photos),
+		caption:   caption,
+		hasMore:   len(snapshot.Photos) < snapshot.TotalPhotos,
+		hasHint:   len(snapshot.Hints) < snapshot.TotalHints,
+	}
+}
+
+// expireGameMessage отмечает в подписи последнего фото раунда, что его время вышло
+func (h *Handler) expireGameMessage(chatID int64, roundID int) {
+	h.gameMessagesMu.Lock()
+	msg, ok := h.gameMessages[chatID]
+	if ok && msg.roundID == roundID {
+		delete(h.gameMessages, chatID)
+	}
+	h.gameMessagesMu.Unlock()
+
+	if ok && msg.roundID == roundID {
+		h.editGameCaption(chatID, msg, msg.caption+"\n⌛ Время вышло")
+	}
+}
+
+// tickTimers раз в timerTick обновляет обратный отсчёт в подписях фото
+func (h *Handler) tickTimers() {
+	ticker := time.NewTicker(timerTick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.gameMessagesMu.Lock()
+		messages := make(map[int64]gameMessage, len(h.gameMessages))
+		for chatID, msg := range h.gameMessages {
+			messages[chatID] = msg
+		}
+		h.gameMessagesMu.Unlock()
+
+		for chatID, msg := range messages {
+			snapshot, err := h.game.Snapshot(msg.key)
+			if err != nil || snapshot.RoundID != msg.roundID || len(snapshot.Photos) != msg.photos || snapshot.Deadline.IsZero() {
+				continue
+			}
+			// Подсказки могли закончиться после отправки фото — кнопку не возвращаем
+			msg.hasHint = len(snapshot.Hints) < snapshot.TotalHints
+			h.setGameMessageHint(chatID, msg.roundID, msg.hasHint)
+			h.editGameCaption(chatID, msg, msg.caption+countdownLine(snapshot.Deadline, msg.hasMore))
+		}
+	}
+}
+
+// setGameMessageHint запоминает, осталась ли у фото раунда кнопка подсказки
+func (h *Handler) setGameMessageHint(chatID int64, roundID int, hasHint bool) {
+	h.gameMessagesMu.Lock()
+	defer h.gameMessagesMu.Unlock()
+
+	if msg, ok := h.gameMessages[chatID]; ok && msg.roundID == roundID {
+		msg.hasHint = hasHint
+		h.gameMessages[chatID] = msg
+	}
+}
+
+// hideHintButton убирает кнопку подсказки у фото, когда все подсказки открыты
+func (h *Handler) hideHintButton(chatID int64, messageID int, key string) {
+	snapshot, err := h.game.Snapshot(key)
+	if err != nil {
+		return
+	}
+	h.setGameMessageHint(chatID, snapshot.RoundID, false)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, GameKeyboard(len(snapshot.Photos) < snapshot.TotalPhotos, false))
+	if _, err := h.bot.Request(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		log.Printf("Error hiding hint button: %v", err)
+	}
+}
+
+func (h *Handler) editGameCaption(chatID int64, msg gameMessage, caption string) {
+	edit := tgbotapi.NewEditMessageCaption(chatID, msg.messageID, caption)
+	markup := GameKeyboard(msg.hasMore, msg.hasHint)
+	edit.ReplyMarkup = &markup
+	if _, err := h.bot.Request(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		log.Printf("Error updating round timer: %v", err)
+	}
+}
+
+// countdownLine — строка обратного отсчёта для подписи фото
+func countdownLine(deadline time.Time, hasMore bool) string {
+	left := max(int(math.Ceil(time.Until(deadline).Seconds())), 0)
+	next := "следующее фото"
+	if !hasMore {
+		next = "ответ"
+	}
+	return fmt.Sprintf("\n⏱ Через %d с откроется %s", left, next)
+}
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+// roleNames — как можно назвать роль в /grant
+var roleNames = map[string]domain.Role{
+	"admin": domain.RoleAdmin, "админ": domain.RoleAdmin, "администратор": domain.RoleAdmin,
+	"judge": domain.RoleJudge, "судья": domain.RoleJudge,
+	"player": domain.RolePlayer, "игрок": domain.RolePlayer,
+}
+
+func roleLabel(role domain.Role) string {
+	switch role {
+	case domain.RoleOwner:
+		return "👑 владелец"
+	case domain.RoleAdmin:
+		return "🛠 администратор"
+	case domain.RoleJudge:
+		return "⚖️ судья"
+	default:
+		return "🎮 игрок"
+	}
+}
+
+// cmdGrant выдаёт роль: `/grant ID РОЛЬ` или ответом на сообщение пользователя `/grant РОЛЬ`
+func (h *Handler) cmdGrant(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageUsers) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только владельцу бота")
+		return
+	}
+
+	args := strings.Fields(msg.CommandArguments())
+	targetID, name, args, ok := h.commandTarget(msg, args)
+	if !ok || len(args) != 1 {
+		h.sendUsers(ctx, msg.Chat.ID, "Выдать роль: `/grant ID РОЛЬ` или ответьте на сообщение пользователя `/grant РОЛЬ`\n"+
+			"Роли: администратор — ситуации и колоды, судья — начисление BazuCoin, игрок — снять роль")
+		return
+	}
+
+	role, known := roleNames[strings.ToLower(args[0])]
+	if !known {
+		h.sendText(msg.Chat.ID, fmt.Sprintf("❌ Не знаю роль «%s». Используйте: администратор, судья, игрок", args[0]))
+		return
+	}
+
+	if err := h.users.Grant(ctx, msg.From.ID, targetID, name, role); err != nil {
+		h.sendUserError(msg.Chat.ID, err)
+		return
+	}
+
+	h.sendText(msg.Chat.ID, fmt.Sprintf("✅ %s — теперь %s", userLabel(targetID, name), roleLabel(role)))
+}
+
+// cmdRevoke снимает роль: `/revoke ID` или ответом на сообщение пользователя `/revoke`
+func (h *Handler) cmdRevoke(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageUsers) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только владельцу бота")
+		return
+	}
+
+	targetID, name, args, ok := h.commandTarget(msg, strings.Fields(msg.CommandArguments()))
+	if !ok || len(args) != 0 {
+		h.sendUsers(ctx, msg.Chat.ID, "Снять роль: `/revoke ID` или ответьте на сообщение пользователя `/revoke`")
+		return
+	}
+
+	if err := h.users.Revoke(ctx, msg.From.ID, targetID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.sendText(msg.Chat.ID, fmt.Sprintf("У %s нет роли", userLabel(targetID, name)))
+			return
+		}
+		h.sendUserError(msg.Chat.ID, err)
+		return
+	}
+
+	h.sendText(msg.Chat.ID, fmt.Sprintf("✅ %s — снова %s", userLabel(targetID, name), roleLabel(domain.RolePlayer)))
+}
+
+// commandTarget определяет, к кому относится команда: к автору сообщения, на которое
+// ответили, или к пользователю, чей ID указан первым аргументом. Возвращает оставшиеся аргументы.
+func (h *Handler) commandTarget(msg *tgbotapi.Message, args []string) (int64, string, []string, bool) {
+	if reply := msg.ReplyToMessage; reply != nil && reply.From != nil && !reply.From.IsBot {
+		return reply.From.ID, displayName(reply.From), args, true
+	}
+
+	if len(args) == 0 {
+		return 0, "", nil, false
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, "", nil, false
+	}
+
+	// Имя известно Telegram, только если пользователь уже писал боту
+	var name string
+	if chat, err := h.bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: id}}); err == nil {
+		name = strings.TrimSpace(chat.FirstName + " " + chat.LastName)
+	}
+	return id, name, args[1:], true
+}
+
+// sendUsers присылает подсказку и список пользователей с ролями
+func (h *Handler) sendUsers(ctx context.Context, chatID int64, hint string) {
+	users, err := h.users.List(ctx)
+	if err != nil {
+		log.Printf("Error listing users: %v", err)
+		h.sendText(chatID, hint)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👥 *Роли*\n\n")
+	for _, u := range users {
+		sb.WriteString(fmt.Sprintf("• %s — %s\n", userLabel(u.TelegramID, u.Name), roleLabel(u.Role)))
+	}
+	sb.WriteString("\n" + hint)
+
+	h.sendText(chatID, sb.String())
+}
+
+func (h *Handler) sendUserError(chatID int64, err error) {
+	switch {
+	case errors.Is(err, service.ErrOwnerRole):
+		h.sendText(chatID, "❌ Владелец задаётся в настройках бота (ADMIN_ID), его роль не меняется")
+	case errors.Is(err, service.ErrForbidden):
+		h.sendText(chatID, "⛔ Эта команда доступна только владельцу бота")
+	default:
+		log.Printf("Error changing role: %v", err)
+		h.sendText(chatID, "Ошибка сохранения роли")
+	}
+}
+
+func displayName(u *tgbotapi.User) string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if name == "" {
+		name = u.UserName
+	}
+	return name
+}
+
+func userLabel(id int64, name string) string {
+	if name == "" {
+		return fmt.Sprintf("`%d`", id)
+	}
+	return fmt.Sprintf("%s (`%d`)", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, name), id)
+}
@@ -0,0 +1,33 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// AdminLinker выдаёт ссылки входа в админ-панель веб-интерфейса
+type AdminLinker interface {
+	LoginURL(userID int64, now time.Time) string
+}
+
+// cmdWeb присылает одноразовую по сроку ссылку входа в админ-панель
+func (h *Handler) cmdWeb(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.can(ctx, msg.From.ID, domain.PermManageContent) {
+		h.sendText(msg.Chat.ID, "⛔ Эта команда доступна только администратору")
+		return
+	}
+
+	// По ссылке входят без пароля, поэтому в общий чат её не отправляем
+	if !msg.Chat.IsPrivate() {
+		h.sendText(msg.Chat.ID, "🔒 Ссылку на админ-панель можно получить только в личном чате с ботом")
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, "🛠 Админ-панель: "+h.admin.LoginURL(msg.From.ID, time.Now())+
+		"\n\nСсылка действует 10 минут, никому её не пересылайте")
+	reply.DisableWebPagePreview = true
+	h.bot.Send(reply)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -11,6 +12,29 @@ type Config struct {
 	AdminID  int64
 	DB       DBConfig
 	WebPort  string
+
+	// Адрес веб-интерфейса снаружи, из него строятся ссылки входа в админ-панель
+	WebURL string
+
+	// Хранилище данных: postgres или memory (демо-режим без базы, данные живут до перезапуска)
+	Storage string
+
+	// Каталог, куда скачиваются фото из Telegram
+	PhotoDir string
+
+	// Ключ подписи ссылок на фото; если пустой, генерируется при старте
+	PhotoURLSecret string
+	// Срок действия подписанной ссылки на фото
+	PhotoURLTTL time.Duration
+
+	// Сколько хранится незавершённый ввод в боте (черновик /add, ожидание BazuCoin)
+	DraftTTL time.Duration
+
+	// С какой похожести (от 0 до 1) догадка засчитывается как правильный ответ
+	AnswerMatchThreshold float64
+
+	// Через сколько без действий ведущего открывается следующее фото, а после последнего — ответ; 0 — без таймера
+	PhotoTimer time.Duration
 }
 
 type DBConfig struct {
@@ -19,6 +43,9 @@ type DBConfig struct {
 	User     string
 	Password string
 	Name     string
+
+	// Применять миграции при старте
+	AutoMigrate bool
 }
 
 func (d DBConfig) DSN() string {
@@ -32,17 +59,50 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid ADMIN_ID: %w", err)
 	}
 
+	db, err := LoadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	photoURLTTL, err := time.ParseDuration(getEnv("PHOTO_URL_TTL", "2h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PHOTO_URL_TTL: %w", err)
+	}
+
+	draftTTL, err := time.ParseDuration(getEnv("DRAFT_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DRAFT_TTL: %w", err)
+	}
+
+	answerThreshold, err := strconv.ParseFloat(getEnv("ANSWER_MATCH_THRESHOLD", "0.8"), 64)
+	if err != nil || answerThreshold <= 0 || answerThreshold > 1 {
+		return nil, fmt.Errorf("invalid ANSWER_MATCH_THRESHOLD: expected a number in (0, 1]")
+	}
+
+	photoTimer, err := time.ParseDuration(getEnv("PHOTO_TIMER", "0"))
+	if err != nil || photoTimer < 0 {
+		return nil, fmt.Errorf("invalid PHOTO_TIMER: expected a duration like 30s or 0 to disable")
+	}
+
 	cfg := &Config{
 		BotToken: getEnv("BOT_TOKEN", ""),
 		AdminID:  adminID,
-		DB: DBConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "quiz"),
-			Password: getEnv("DB_PASSWORD", ""),
-			Name:     getEnv("DB_NAME", "photo_quiz"),
-		},
-		WebPort: getEnv("WEB_PORT", "8080"),
+		DB:       *db,
+		WebPort:  getEnv("WEB_PORT", "8080"),
+		WebURL:   getEnv("WEB_URL", "http://localhost:8080"),
+		Storage:  getEnv("STORAGE", "postgres"),
+		PhotoDir: getEnv("PHOTO_DIR", "data/photos"),
+
+		PhotoURLSecret: getEnv("PHOTO_URL_SECRET", ""),
+		PhotoURLTTL:    photoURLTTL,
+		DraftTTL:       draftTTL,
+
+		AnswerMatchThreshold: answerThreshold,
+		PhotoTimer:           photoTimer,
+	}
+
+	if cfg.Storage != "postgres" && cfg.Storage != "memory" {
+		return nil, fmt.Errorf("invalid STORAGE: %q (expected postgres or memory)", cfg.Storage)
 	}
 
 	if cfg.BotToken == "" {
@@ -52,6 +112,23 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// LoadDB загружает только настройки базы данных (для подкоманды migrate)
+func LoadDB() (*DBConfig, error) {
+	autoMigrate, err := strconv.ParseBool(getEnv("DB_AUTO_MIGRATE", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_AUTO_MIGRATE: %w", err)
+	}
+
+	return &DBConfig{
+		Host:        getEnv("DB_HOST", "localhost"),
+		Port:        getEnv("DB_PORT", "5432"),
+		User:        getEnv("DB_USER", "quiz"),
+		Password:    getEnv("DB_PASSWORD", ""),
+		Name:        getEnv("DB_NAME", "photo_quiz"),
+		AutoMigrate: autoMigrate,
+	}, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
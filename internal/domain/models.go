@@ -1,12 +1,80 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Уровни сложности ситуаций
+const (
+	DifficultyEasy   = 1
+	DifficultyMedium = 2
+	DifficultyHard   = 3
+)
+
+// MaxPhotosPerSituation — сколько фото может быть у одной ситуации
+const MaxPhotosPerSituation = 5
+
+// MaxAliases — сколько синонимов ответа может быть у одной ситуации
+const MaxAliases = 20
+
+// MaxHints — сколько текстовых подсказок может быть у одной ситуации
+const MaxHints = 5
+
+// Difficulties — все уровни сложности по возрастанию
+var Difficulties = []int{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+// NormalizeAnswer приводит ответ к виду для сравнения: нижний регистр, «ё» как «е»,
+// знаки препинания и лишние пробелы убраны
+func NormalizeAnswer(answer string) string {
+	answer = strings.ReplaceAll(strings.ToLower(answer), "ё", "е")
+	words := strings.FieldsFunc(answer, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// CleanAliases убирает из синонимов пустые, повторы и совпадающие с ответом (после нормализации)
+func CleanAliases(answer string, aliases []string) []string {
+	seen := map[string]bool{NormalizeAnswer(answer): true}
+	var clean []string
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		normalized := NormalizeAnswer(alias)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		clean = append(clean, alias)
+	}
+	return clean
+}
+
+// CleanHints убирает пробелы по краям и пустые подсказки, сохраняя их порядок
+func CleanHints(hints []string) []string {
+	var clean []string
+	for _, hint := range hints {
+		if hint = strings.TrimSpace(hint); hint != "" {
+			clean = append(clean, hint)
+		}
+	}
+	return clean
+}
 
 type Situation struct {
-	ID        int
-	Answer    string
-	IsUsed    bool
-	CreatedAt time.Time
+	ID         int
+	Answer     string
+	Aliases    []string // другие варианты ответа, которые засчитываются как правильные
+	Hints      []string // текстовые подсказки по порядку; каждая открытая уменьшает очки за ход
+	Difficulty int
+	IsUsed     bool
+	CreatedAt  time.Time
+}
+
+// AcceptedAnswers возвращает ответ и все его синонимы
+func (s Situation) AcceptedAnswers() []string {
+	return append([]string{s.Answer}, s.Aliases...)
 }
 
 type Photo struct {
@@ -15,7 +83,14 @@ type Photo struct {
 	FileID      string
 	OrderNum    int
 	SortOrder   int
+	StorageKey  string // ключ в хранилище фото; пусто, пока фото не скачано из Telegram
+	ContentType string
 	CreatedAt   time.Time
+
+	// Отпечаток для поиска дублей: file_unique_id в Telegram (один и тот же файл)
+	// и перцептивный хеш изображения (похожее фото); пустые, пока не известны
+	FileUniqueID string
+	Hash         uint64
 }
 
 type SituationWithPhotos struct {
@@ -23,25 +98,198 @@ type SituationWithPhotos struct {
 	Photos    []Photo
 }
 
+// NewSituation — ситуация для пакетного создания (импорт архива)
+type NewSituation struct {
+	Answer       string
+	Aliases      []string
+	Hints        []string
+	Difficulty   int
+	IsUsed       bool
+	DeckIDs      []int
+	PhotoFileIDs []string
+}
+
+// SituationUpdate — изменения ситуации, которые сохраняются вместе; nil-поля не меняются
+type SituationUpdate struct {
+	Answer     *string
+	Aliases    *[]string
+	Hints      *[]string
+	Difficulty *int
+	DeckIDs    *[]int
+	IsUsed     *bool
+}
+
+// SituationSummary — ситуация в списке для администратора
+type SituationSummary struct {
+	Situation
+	PhotoCount int
+}
+
+// Deck — тематическая колода ситуаций (фильмы, офис, путешествия...)
+type Deck struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// SituationFilter ограничивает выбор ситуаций для игры; пустой фильтр — все ситуации
+type SituationFilter struct {
+	DeckIDs      []int
+	Difficulties []int
+	Balanced     bool // чередовать сложности от раунда к раунду
+}
+
+func (f SituationFilter) IsEmpty() bool {
+	return len(f.DeckIDs) == 0 && len(f.Difficulties) == 0 && !f.Balanced
+}
+
+// Role — роль пользователя Telegram в боте
+type Role string
+
+const (
+	RolePlayer Role = "player" // обычный игрок; роль по умолчанию, в базе не хранится
+	RoleJudge  Role = "judge"  // начисляет BazuCoin за ходы
+	RoleAdmin  Role = "admin"  // управляет ситуациями и колодами, сбрасывает игру
+	RoleOwner  Role = "owner"  // владелец бота (ADMIN_ID), выдаёт и отзывает роли
+)
+
+// Roles — все роли по возрастанию прав
+var Roles = []Role{RolePlayer, RoleJudge, RoleAdmin, RoleOwner}
+
+// Permission — действие, доступное не всем пользователям
+type Permission int
+
+const (
+	PermScore         Permission = iota // начислять BazuCoin
+	PermManageContent                   // добавлять, править и удалять ситуации, колоды, сбрасывать игру
+	PermManageUsers                     // выдавать и отзывать роли
+)
+
+// Can сообщает, разрешено ли роли действие
+func (r Role) Can(p Permission) bool {
+	switch p {
+	case PermScore:
+		return r == RoleJudge || r == RoleAdmin || r == RoleOwner
+	case PermManageContent:
+		return r == RoleAdmin || r == RoleOwner
+	case PermManageUsers:
+		return r == RoleOwner
+	}
+	return false
+}
+
+func (r Role) IsValid() bool {
+	for _, role := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// User — пользователь Telegram с ролью выше игрока
+type User struct {
+	TelegramID int64
+	Name       string
+	Role       Role
+	GrantedBy  int64
+	CreatedAt  time.Time
+}
+
+// Виды черновиков: незавершённый ввод пользователя в боте
+const (
+	DraftAdd   = "add"   // черновик новой ситуации (/add)
+	DraftScore = "score" // ожидание BazuCoin за ход от судьи
+)
+
+// Draft — незавершённый ввод пользователя в боте; хранится, чтобы пережить перезапуск
+type Draft struct {
+	UserID    int64
+	Kind      string
+	Data      []byte // состояние в JSON
+	UpdatedAt time.Time
+}
+
+// Статусы предложенных игроками ситуаций
+const (
+	SubmissionPending  = "pending"  // ждёт модерации
+	SubmissionApproved = "approved" // одобрено и перенесено в ситуации
+	SubmissionRejected = "rejected" // отклонено
+)
+
+// Submission — ситуация, предложенная игроком через /suggest; попадает в игру после одобрения
+type Submission struct {
+	ID             int
+	AuthorID       int64
+	AuthorName     string
+	Answer         string
+	Difficulty     int
+	PhotoFileIDs   []string
+	PhotoUniqueIDs []string // file_unique_id фото в том же порядке; пустые, если не известны
+	Status         string
+	ReviewedBy     int64
+	SituationID    int // ситуация, созданная при одобрении
+	CreatedAt      time.Time
+}
+
 type Player struct {
-	ID    string  `json:"id"`
-	Name  string  `json:"name"`
-	Score float64 `json:"score"`
-	Order int     `json:"order"`
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+	Order  int     `json:"order"`
+	TeamID string  `json:"teamId,omitempty"` // команда игрока в командной игре
+}
+
+// Team — команда веб-комнаты в командной игре: ход переходит от команды к команде,
+// а BazuCoin участников складываются в счёт команды
+type Team struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Order int    `json:"order"`
 }
 
 type GameSession struct {
 	ID              string    `json:"id"`
+	Code            string    `json:"code"`
 	Players         []Player  `json:"players"`
+	Teams           []Team    `json:"teams,omitempty"` // пусто — каждый играет сам за себя
+	RotateMembers   bool      `json:"rotateMembers,omitempty"` // в командной игре участники команды отвечают по очереди, иначе — капитан
 	CurrentPlayerID string    `json:"currentPlayerId"`
 	CurrentRound    int       `json:"currentRound"`
 	IsActive        bool      `json:"isActive"`
 	IsFinished      bool      `json:"isFinished"`
+	DeckIDs         []int     `json:"deckIds,omitempty"`
+	Difficulties    []int     `json:"difficulties,omitempty"`
+	Balanced        bool      `json:"balanced,omitempty"`
+	PhotoSeconds    int       `json:"photoSeconds,omitempty"` // таймер на фото, 0 — без таймера
+	ScoreRule       []float64 `json:"scoreRule,omitempty"`    // BazuCoin по числу открытых фото, пусто — очки выбирает судья
 	CreatedAt       time.Time `json:"createdAt"`
 }
 
+// Filter возвращает фильтр ситуаций, выбранный при создании комнаты
+func (s *GameSession) Filter() SituationFilter {
+	return SituationFilter{
+		DeckIDs:      s.DeckIDs,
+		Difficulties: s.Difficulties,
+		Balanced:     s.Balanced,
+	}
+}
+
+// PlayerScore — строка таблицы очков: игрок или, в командной игре, команда
 type PlayerScore struct {
-	Name            string  `json:"name"`
-	Score           float64 `json:"score"`
-	IsCurrentPlayer bool    `json:"isCurrentPlayer"`
-}
\ No newline at end of file
+	Name            string   `json:"name"`
+	Score           float64  `json:"score"`
+	IsCurrentPlayer bool     `json:"isCurrentPlayer"`
+	Members         []string `json:"members,omitempty"` // участники команды
+}
+
+// ChatPlayerScore — итоги игрока в режиме угадывания в одном чате Telegram
+type ChatPlayerScore struct {
+	ChatID  int64   `json:"chatId"`
+	UserID  int64   `json:"userId"`
+	Name    string  `json:"name"`
+	Score   float64 `json:"score"`
+	Correct int     `json:"correct"` // угаданные ситуации
+	Wrong   int     `json:"wrong"`   // неверные догадки
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+// SituationRepository — хранилище ситуаций и их фотографий
+type SituationRepository interface {
+	CreateSituation(ctx context.Context, answer string, difficulty int) (int, error)
+	AddPhoto(ctx context.Context, situationID int, fileID string) (int, error)
+	AddPhotos(ctx context.Context, situationID int, fileIDs []string) ([]int, error)
+	Create(ctx context.Context, answer string, difficulty int, photoFileIDs []string) (int, error)
+	CreateBatch(ctx context.Context, situations []NewSituation) ([]int, error)
+	GetRandomUnused(ctx context.Context, filter SituationFilter) (*SituationWithPhotos, error)
+	MarkAsUsed(ctx context.Context, situationID int) error
+	ResetAllUsed(ctx context.Context) error
+	GetByID(ctx context.Context, id int) (*SituationWithPhotos, error)
+	List(ctx context.Context, offset, limit int) ([]SituationSummary, int, error)
+	Search(ctx context.Context, query string, offset, limit int) ([]SituationSummary, int, error)
+	UpdateAnswer(ctx context.Context, id int, answer string) error
+	SetAliases(ctx context.Context, id int, aliases []string) error
+	SetHints(ctx context.Context, id int, hints []string) error
+	UpdateDifficulty(ctx context.Context, id int, difficulty int) error
+	SetUsed(ctx context.Context, id int, used bool) error
+	Update(ctx context.Context, id int, update SituationUpdate) error
+	Delete(ctx context.Context, id int) error
+	CountPhotos(ctx context.Context, situationID int) (int, error)
+	GetPhotoByID(ctx context.Context, photoID int) (*Photo, error)
+	SetPhotoStorage(ctx context.Context, photoID int, storageKey, contentType string) error
+	DeletePhoto(ctx context.Context, photoID int) error
+	ReorderPhotos(ctx context.Context, situationID int, photoIDs []int) error
+	SetPhotoFingerprint(ctx context.Context, photoID int, fileUniqueID string, hash uint64) error
+	ListPhotoFingerprints(ctx context.Context) ([]Photo, error)
+	ListUnhashedPhotos(ctx context.Context) ([]Photo, error)
+	ListStorageKeys(ctx context.Context) ([]string, error)
+	FindByAnswer(ctx context.Context, normalized string) ([]int, error)
+	SetDecks(ctx context.Context, situationID int, deckIDs []int) error
+	GetDeckIDs(ctx context.Context, situationID int) ([]int, error)
+	GetStats(ctx context.Context, filter SituationFilter) (total, used int, err error)
+	DeleteAll(ctx context.Context) (int, error)
+}
+
+// DeckRepository — хранилище тематических колод
+type DeckRepository interface {
+	Create(ctx context.Context, name, description string) (*Deck, error)
+	List(ctx context.Context) ([]Deck, error)
+	GetByName(ctx context.Context, name string) (*Deck, error)
+}
+
+// UserRepository — хранилище ролей пользователей Telegram
+type UserRepository interface {
+	Get(ctx context.Context, telegramID int64) (*User, error)
+	Save(ctx context.Context, user *User) error
+	Delete(ctx context.Context, telegramID int64) error
+	List(ctx context.Context) ([]User, error)
+}
+
+// DraftRepository — хранилище черновиков: у пользователя не больше одного черновика каждого вида
+type DraftRepository interface {
+	Get(ctx context.Context, userID int64, kind string) (*Draft, error)
+	Save(ctx context.Context, draft *Draft) error
+	Delete(ctx context.Context, userID int64, kind string) error
+	List(ctx context.Context, kind string) ([]Draft, error)
+	DeleteOlder(ctx context.Context, before time.Time) (int, error)
+}
+
+// SubmissionRepository — очередь предложенных игроками ситуаций
+type SubmissionRepository interface {
+	Create(ctx context.Context, submission *Submission) error
+	Get(ctx context.Context, id int) (*Submission, error)
+	ListPending(ctx context.Context) ([]Submission, error)
+	// UpdateAnswer меняет ответ предложения, пока оно ждёт модерации
+	UpdateAnswer(ctx context.Context, id int, answer string) error
+	// Review закрывает предложение со статусом status; ErrNotFound — если оно уже не ждёт модерации
+	Review(ctx context.Context, id int, status string, reviewedBy int64, situationID int) error
+}
+
+// SessionRepository — хранилище веб-комнат, игроков и начисленных BazuCoin
+type SessionRepository interface {
+	Create(ctx context.Context, session *GameSession) error
+	ListActive(ctx context.Context) ([]*GameSession, error)
+	AddScore(ctx context.Context, sessionID, playerID string, round int, score float64) error
+	UpdateTurn(ctx context.Context, sessionID, currentPlayerID string, round int) error
+	Finish(ctx context.Context, sessionID string) error
+}
+
+// ChatScoreRepository — очки игроков чатов Telegram в режиме угадывания
+type ChatScoreRepository interface {
+	// Add прибавляет к итогам игрока очки и счётчики догадок, заодно обновляя его имя
+	Add(ctx context.Context, chatID, userID int64, name string, score float64, correct, wrong int) error
+	// List возвращает итоги игроков чата: сначала больше очков
+	List(ctx context.Context, chatID int64) ([]ChatPlayerScore, error)
+	Reset(ctx context.Context, chatID int64) error
+}
@@ -0,0 +1,74 @@
+// Package imagehash считает перцептивный хеш изображений для поиска похожих фото:
+// у пересжатой, уменьшенной или слегка подрезанной копии хеш почти не меняется.
+package imagehash
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math/bits"
+
+	// Форматы, которые умеет декодировать DHash
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// DHash возвращает разностный хеш (dHash) изображения: картинка сжимается до 9×8 оттенков
+// серого, и каждый бит говорит, светлее ли точка соседней справа
+func DHash(data []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("decode image: %w", err)
+	}
+
+	const w, h = 9, 8
+	var gray [h][w]float64
+	b := img.Bounds()
+	if b.Dx() < w || b.Dy() < h {
+		return 0, fmt.Errorf("image is too small: %dx%d", b.Dx(), b.Dy())
+	}
+
+	// Средняя яркость каждой клетки сетки 9×8
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			gray[y][x] = average(img, x0, y0, x1, y1)
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+// Distance — число различающихся бит двух хешей; у похожих изображений оно мало
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// average возвращает среднюю яркость прямоугольника; большие клетки считаются
+// по сетке не больше 16×16 точек, этого достаточно для хеша
+func average(img image.Image, x0, y0, x1, y1 int) float64 {
+	stepX := max((x1-x0)/16, 1)
+	stepY := max((y1-y0)/16, 1)
+
+	var sum float64
+	var n int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			n++
+		}
+	}
+	return sum / float64(n)
+}
@@ -0,0 +1,126 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// gradient рисует картинку w×h: горизонтальный градиент с тёмным квадратом в левом верхнем углу
+func gradient(w, h int, invert bool) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / w)
+			if x < w/3 && y < h/3 {
+				v /= 4
+			}
+			if invert {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, img image.Image, quality int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDHashSimilarImages(t *testing.T) {
+	original, err := DHash(encodePNG(t, gradient(320, 240, false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		maxDist int
+	}{
+		{"тот же файл", encodePNG(t, gradient(320, 240, false)), 0},
+		{"пересжатый JPEG", encodeJPEG(t, gradient(320, 240, false), 40), 4},
+		{"уменьшенная копия", encodePNG(t, gradient(80, 60, false)), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := DHash(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := Distance(original, hash); d > tt.maxDist {
+				t.Errorf("Distance = %d, want at most %d", d, tt.maxDist)
+			}
+		})
+	}
+}
+
+func TestDHashDifferentImages(t *testing.T) {
+	a, err := DHash(encodePNG(t, gradient(320, 240, false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := DHash(encodePNG(t, gradient(320, 240, true)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := Distance(a, b); d < 32 {
+		t.Errorf("Distance of inverted image = %d, want at least 32", d)
+	}
+}
+
+func TestDHashErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"пустой файл", nil},
+		{"не изображение", []byte("not an image")},
+		{"меньше сетки 9×8", encodePNG(t, gradient(8, 8, false))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if hash, err := DHash(tt.data); err == nil {
+				t.Errorf("DHash() = %x, want error", hash)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0b1011, 0b1011, 0},
+		{0b1011, 0b0010, 2},
+		{0, ^uint64(0), 64},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%b, %b) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.ChatScoreRepository = (*ChatScoreRepository)(nil)
+
+type chatScoreKey struct {
+	chatID int64
+	userID int64
+}
+
+type ChatScoreRepository struct {
+	scores map[chatScoreKey]domain.ChatPlayerScore
+	mu     sync.RWMutex
+}
+
+func NewChatScoreRepository() *ChatScoreRepository {
+	return &ChatScoreRepository{
+		scores: make(map[chatScoreKey]domain.ChatPlayerScore),
+	}
+}
+
+func (r *ChatScoreRepository) Add(ctx context.Context, chatID, userID int64, name string, score float64, correct, wrong int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := chatScoreKey{chatID, userID}
+	s := r.scores[key]
+	s.ChatID, s.UserID, s.Name = chatID, userID, name
+	s.Score += score
+	s.Correct += correct
+	s.Wrong += wrong
+	r.scores[key] = s
+	return nil
+}
+
+func (r *ChatScoreRepository) List(ctx context.Context, chatID int64) ([]domain.ChatPlayerScore, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var scores []domain.ChatPlayerScore
+	for key, s := range r.scores {
+		if key.chatID == chatID {
+			scores = append(scores, s)
+		}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		if scores[i].Correct != scores[j].Correct {
+			return scores[i].Correct > scores[j].Correct
+		}
+		return scores[i].UserID < scores[j].UserID
+	})
+	return scores, nil
+}
+
+func (r *ChatScoreRepository) Reset(ctx context.Context, chatID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.scores {
+		if key.chatID == chatID {
+			delete(r.scores, key)
+		}
+	}
+	return nil
+}
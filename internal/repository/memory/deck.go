@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.DeckRepository = (*DeckRepository)(nil)
+
+type DeckRepository struct {
+	decks  map[int]domain.Deck
+	nextID int
+	mu     sync.RWMutex
+}
+
+func NewDeckRepository() *DeckRepository {
+	return &DeckRepository{
+		decks: make(map[int]domain.Deck),
+	}
+}
+
+func (r *DeckRepository) Create(ctx context.Context, name, description string) (*domain.Deck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findByName(name) != nil {
+		return nil, domain.ErrAlreadyExists
+	}
+
+	r.nextID++
+	d := domain.Deck{
+		ID:          r.nextID,
+		Name:        name,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	r.decks[d.ID] = d
+	return &d, nil
+}
+
+func (r *DeckRepository) List(ctx context.Context) ([]domain.Deck, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	decks := make([]domain.Deck, 0, len(r.decks))
+	for _, d := range r.decks {
+		decks = append(decks, d)
+	}
+	sort.Slice(decks, func(i, j int) bool {
+		return decks[i].Name < decks[j].Name
+	})
+	return decks, nil
+}
+
+func (r *DeckRepository) GetByName(ctx context.Context, name string) (*domain.Deck, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if d := r.findByName(name); d != nil {
+		return d, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+// findByName ищет колоду без учёта регистра; вызывать под блокировкой
+func (r *DeckRepository) findByName(name string) *domain.Deck {
+	for _, d := range r.decks {
+		if strings.EqualFold(d.Name, name) {
+			return &d
+		}
+	}
+	return nil
+}
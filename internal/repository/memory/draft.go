@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.DraftRepository = (*DraftRepository)(nil)
+
+type draftKey struct {
+	userID int64
+	kind   string
+}
+
+type DraftRepository struct {
+	drafts map[draftKey]domain.Draft
+	mu     sync.RWMutex
+}
+
+func NewDraftRepository() *DraftRepository {
+	return &DraftRepository{
+		drafts: make(map[draftKey]domain.Draft),
+	}
+}
+
+func (r *DraftRepository) Get(ctx context.Context, userID int64, kind string) (*domain.Draft, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.drafts[draftKey{userID, kind}]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	d.Data = slices.Clone(d.Data)
+	return &d, nil
+}
+
+func (r *DraftRepository) Save(ctx context.Context, draft *domain.Draft) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	draft.UpdatedAt = time.Now()
+	d := *draft
+	d.Data = slices.Clone(draft.Data)
+	r.drafts[draftKey{draft.UserID, draft.Kind}] = d
+	return nil
+}
+
+func (r *DraftRepository) Delete(ctx context.Context, userID int64, kind string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := draftKey{userID, kind}
+	if _, ok := r.drafts[key]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.drafts, key)
+	return nil
+}
+
+func (r *DraftRepository) List(ctx context.Context, kind string) ([]domain.Draft, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var drafts []domain.Draft
+	for key, d := range r.drafts {
+		if key.kind == kind {
+			d.Data = slices.Clone(d.Data)
+			drafts = append(drafts, d)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool {
+		if !drafts[i].UpdatedAt.Equal(drafts[j].UpdatedAt) {
+			return drafts[i].UpdatedAt.Before(drafts[j].UpdatedAt)
+		}
+		return drafts[i].UserID < drafts[j].UserID
+	})
+	return drafts, nil
+}
+
+func (r *DraftRepository) DeleteOlder(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for key, d := range r.drafts {
+		if d.UpdatedAt.Before(before) {
+			delete(r.drafts, key)
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.SessionRepository = (*SessionRepository)(nil)
+
+type SessionRepository struct {
+	sessions map[string]*domain.GameSession
+	mu       sync.RWMutex
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		sessions: make(map[string]*domain.GameSession),
+	}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *domain.GameSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[session.ID] = cloneSession(session)
+	return nil
+}
+
+func (r *SessionRepository) ListActive(ctx context.Context) ([]*domain.GameSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var sessions []*domain.GameSession
+	for _, s := range r.sessions {
+		if s.IsActive {
+			sessions = append(sessions, cloneSession(s))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (r *SessionRepository) AddScore(ctx context.Context, sessionID, playerID string, round int, score float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[sessionID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	for i := range s.Players {
+		if s.Players[i].ID == playerID {
+			s.Players[i].Score += score
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *SessionRepository) UpdateTurn(ctx context.Context, sessionID, currentPlayerID string, round int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.sessions[sessionID]; ok {
+		s.CurrentPlayerID = currentPlayerID
+		s.CurrentRound = round
+	}
+	return nil
+}
+
+func (r *SessionRepository) Finish(ctx context.Context, sessionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.sessions[sessionID]; ok {
+		s.IsActive = false
+		s.IsFinished = true
+	}
+	return nil
+}
+
+func cloneSession(s *domain.GameSession) *domain.GameSession {
+	c := *s
+	c.Players = append([]domain.Player(nil), s.Players...)
+	c.DeckIDs = append([]int(nil), s.DeckIDs...)
+	c.Difficulties = append([]int(nil), s.Difficulties...)
+	c.ScoreRule = append([]float64(nil), s.ScoreRule...)
+	c.Teams = append([]domain.Team(nil), s.Teams...)
+	return &c
+}
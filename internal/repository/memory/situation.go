@@ -0,0 +1,578 @@
+// Package memory — хранилища в памяти процесса с той же семантикой, что и postgres.
+// Используются в демо-режиме без базы данных и в тестах.
+package memory
+
+import (
+	"context"
+	"maps"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.SituationRepository = (*SituationRepository)(nil)
+
+type SituationRepository struct {
+	situations  map[int]*domain.Situation
+	photos      map[int][]domain.Photo // ключ — ID ситуации
+	decks       map[int][]int          // колоды ситуации; ключ — ID ситуации
+	nextID      int
+	nextPhotoID int
+	mu          sync.RWMutex
+}
+
+func NewSituationRepository() *SituationRepository {
+	return &SituationRepository{
+		situations: make(map[int]*domain.Situation),
+		photos:     make(map[int][]domain.Photo),
+		decks:      make(map[int][]int),
+	}
+}
+
+func (r *SituationRepository) CreateSituation(ctx context.Context, answer string, difficulty int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	r.situations[r.nextID] = &domain.Situation{
+		ID:         r.nextID,
+		Answer:     answer,
+		Difficulty: difficulty,
+		CreatedAt:  time.Now(),
+	}
+	return r.nextID, nil
+}
+
+func (r *SituationRepository) AddPhoto(ctx context.Context, situationID int, fileID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.situations[situationID]; !ok {
+		return 0, domain.ErrNotFound
+	}
+
+	sortOrder := 0
+	for _, p := range r.photos[situationID] {
+		if p.SortOrder >= sortOrder {
+			sortOrder = p.SortOrder + 1
+		}
+	}
+
+	return r.appendPhoto(situationID, fileID, sortOrder), nil
+}
+
+func (r *SituationRepository) AddPhotos(ctx context.Context, situationID int, fileIDs []string) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.situations[situationID]; !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	sortOrder := 0
+	for _, p := range r.photos[situationID] {
+		if p.SortOrder >= sortOrder {
+			sortOrder = p.SortOrder + 1
+		}
+	}
+
+	ids := make([]int, 0, len(fileIDs))
+	for i, fileID := range fileIDs {
+		ids = append(ids, r.appendPhoto(situationID, fileID, sortOrder+i))
+	}
+	return ids, nil
+}
+
+// appendPhoto добавляет фото в конец ситуации; вызывать под блокировкой
+func (r *SituationRepository) appendPhoto(situationID int, fileID string, sortOrder int) int {
+	r.nextPhotoID++
+	r.photos[situationID] = append(r.photos[situationID], domain.Photo{
+		ID:          r.nextPhotoID,
+		SituationID: situationID,
+		FileID:      fileID,
+		OrderNum:    sortOrder,
+		SortOrder:   sortOrder,
+		CreatedAt:   time.Now(),
+	})
+	return r.nextPhotoID
+}
+
+func (r *SituationRepository) Create(ctx context.Context, answer string, difficulty int, photoFileIDs []string) (int, error) {
+	situationID, err := r.CreateSituation(ctx, answer, difficulty)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, fileID := range photoFileIDs {
+		if _, err := r.AddPhoto(ctx, situationID, fileID); err != nil {
+			return 0, err
+		}
+	}
+
+	return situationID, nil
+}
+
+func (r *SituationRepository) CreateBatch(ctx context.Context, situations []domain.NewSituation) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]int, 0, len(situations))
+	for _, s := range situations {
+		r.nextID++
+		id := r.nextID
+		r.situations[id] = &domain.Situation{
+			ID:         id,
+			Answer:     s.Answer,
+			Aliases:    slices.Clone(s.Aliases),
+			Hints:      slices.Clone(s.Hints),
+			Difficulty: s.Difficulty,
+			IsUsed:     s.IsUsed,
+			CreatedAt:  time.Now(),
+		}
+
+		for i, fileID := range s.PhotoFileIDs {
+			r.nextPhotoID++
+			r.photos[id] = append(r.photos[id], domain.Photo{
+				ID:          r.nextPhotoID,
+				SituationID: id,
+				FileID:      fileID,
+				OrderNum:    i,
+				SortOrder:   i,
+				CreatedAt:   time.Now(),
+			})
+		}
+
+		if len(s.DeckIDs) > 0 {
+			r.decks[id] = append([]int(nil), s.DeckIDs...)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *SituationRepository) GetRandomUnused(ctx context.Context, filter domain.SituationFilter) (*domain.SituationWithPhotos, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var unused []int
+	for id, s := range r.situations {
+		if !s.IsUsed && r.matches(id, filter) {
+			unused = append(unused, id)
+		}
+	}
+	if len(unused) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return r.withPhotos(unused[rand.Intn(len(unused))]), nil
+}
+
+func (r *SituationRepository) MarkAsUsed(ctx context.Context, situationID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.situations[situationID]; ok {
+		s.IsUsed = true
+	}
+	return nil
+}
+
+func (r *SituationRepository) ResetAllUsed(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.situations {
+		s.IsUsed = false
+	}
+	return nil
+}
+
+func (r *SituationRepository) GetByID(ctx context.Context, id int) (*domain.SituationWithPhotos, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.situations[id]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	return r.withPhotos(id), nil
+}
+
+func (r *SituationRepository) List(ctx context.Context, offset, limit int) ([]domain.SituationSummary, int, error) {
+	return r.Search(ctx, "", offset, limit)
+}
+
+func (r *SituationRepository) Search(ctx context.Context, query string, offset, limit int) ([]domain.SituationSummary, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	ids := make([]int, 0, len(r.situations))
+	for id, s := range r.situations {
+		if query == "" || strings.Contains(strings.ToLower(s.Answer), query) || strconv.Itoa(id) == strings.TrimPrefix(query, "#") {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	total := len(ids)
+	if offset > total {
+		offset = total
+	}
+	ids = ids[offset:min(offset+limit, total)]
+
+	situations := make([]domain.SituationSummary, 0, len(ids))
+	for _, id := range ids {
+		situations = append(situations, domain.SituationSummary{
+			Situation:  *r.situations[id],
+			PhotoCount: len(r.photos[id]),
+		})
+	}
+	return situations, total, nil
+}
+
+func (r *SituationRepository) UpdateAnswer(ctx context.Context, id int, answer string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.situations[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	s.Answer = answer
+	return nil
+}
+
+func (r *SituationRepository) SetAliases(ctx context.Context, id int, aliases []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.situations[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	s.Aliases = slices.Clone(aliases)
+	return nil
+}
+
+func (r *SituationRepository) SetHints(ctx context.Context, id int, hints []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.situations[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	s.Hints = slices.Clone(hints)
+	return nil
+}
+
+func (r *SituationRepository) UpdateDifficulty(ctx context.Context, id int, difficulty int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.situations[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	s.Difficulty = difficulty
+	return nil
+}
+
+func (r *SituationRepository) SetUsed(ctx context.Context, id int, used bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.situations[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	s.IsUsed = used
+	return nil
+}
+
+func (r *SituationRepository) Update(ctx context.Context, id int, update domain.SituationUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.situations[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if update.Answer != nil {
+		s.Answer = *update.Answer
+	}
+	if update.Aliases != nil {
+		s.Aliases = slices.Clone(*update.Aliases)
+	}
+	if update.Hints != nil {
+		s.Hints = slices.Clone(*update.Hints)
+	}
+	if update.Difficulty != nil {
+		s.Difficulty = *update.Difficulty
+	}
+	if update.IsUsed != nil {
+		s.IsUsed = *update.IsUsed
+	}
+	if update.DeckIDs != nil {
+		r.decks[id] = append([]int(nil), *update.DeckIDs...)
+	}
+	return nil
+}
+
+func (r *SituationRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.situations[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.situations, id)
+	delete(r.photos, id)
+	delete(r.decks, id)
+	return nil
+}
+
+func (r *SituationRepository) CountPhotos(ctx context.Context, situationID int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.photos[situationID]), nil
+}
+
+func (r *SituationRepository) GetPhotoByID(ctx context.Context, photoID int) (*domain.Photo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if p := r.findPhoto(photoID); p != nil {
+		photo := *p
+		return &photo, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *SituationRepository) SetPhotoStorage(ctx context.Context, photoID int, storageKey, contentType string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p := r.findPhoto(photoID); p != nil {
+		p.StorageKey = storageKey
+		p.ContentType = contentType
+	}
+	return nil
+}
+
+func (r *SituationRepository) SetPhotoFingerprint(ctx context.Context, photoID int, fileUniqueID string, hash uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p := r.findPhoto(photoID); p != nil {
+		if fileUniqueID != "" {
+			p.FileUniqueID = fileUniqueID
+		}
+		if hash != 0 {
+			p.Hash = hash
+		}
+	}
+	return nil
+}
+
+func (r *SituationRepository) ListPhotoFingerprints(ctx context.Context) ([]domain.Photo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var photos []domain.Photo
+	for _, id := range slices.Sorted(maps.Keys(r.situations)) {
+		for _, p := range r.withPhotos(id).Photos {
+			if p.FileUniqueID != "" || p.Hash != 0 {
+				photos = append(photos, p)
+			}
+		}
+	}
+	return photos, nil
+}
+
+func (r *SituationRepository) ListUnhashedPhotos(ctx context.Context) ([]domain.Photo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var photos []domain.Photo
+	for _, id := range slices.Sorted(maps.Keys(r.situations)) {
+		for _, p := range r.withPhotos(id).Photos {
+			if p.Hash == 0 {
+				photos = append(photos, p)
+			}
+		}
+	}
+	return photos, nil
+}
+
+func (r *SituationRepository) ListStorageKeys(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []string
+	for _, id := range slices.Sorted(maps.Keys(r.photos)) {
+		for _, p := range r.photos[id] {
+			if p.StorageKey != "" {
+				keys = append(keys, p.StorageKey)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (r *SituationRepository) FindByAnswer(ctx context.Context, normalized string) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []int
+	for id, s := range r.situations {
+		if domain.NormalizeAnswer(s.Answer) == normalized {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (r *SituationRepository) DeletePhoto(ctx context.Context, photoID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for situationID, photos := range r.photos {
+		for i := range photos {
+			if photos[i].ID == photoID {
+				r.photos[situationID] = slices.Delete(photos, i, i+1)
+				return nil
+			}
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *SituationRepository) SetDecks(ctx context.Context, situationID int, deckIDs []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.situations[situationID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.decks[situationID] = append([]int(nil), deckIDs...)
+	return nil
+}
+
+func (r *SituationRepository) ReorderPhotos(ctx context.Context, situationID int, photoIDs []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	photos := r.photos[situationID]
+	if len(photoIDs) != len(photos) {
+		return domain.ErrNotFound
+	}
+	order := make(map[int]int, len(photoIDs))
+	for i, id := range photoIDs {
+		order[id] = i
+	}
+	for _, p := range photos {
+		if _, ok := order[p.ID]; !ok {
+			return domain.ErrNotFound
+		}
+	}
+
+	for i := range photos {
+		photos[i].SortOrder = order[photos[i].ID]
+		photos[i].OrderNum = photos[i].SortOrder
+	}
+	return nil
+}
+
+func (r *SituationRepository) GetDeckIDs(ctx context.Context, situationID int) ([]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.situations[situationID]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	deckIDs := append([]int(nil), r.decks[situationID]...)
+	slices.Sort(deckIDs)
+	return deckIDs, nil
+}
+
+func (r *SituationRepository) GetStats(ctx context.Context, filter domain.SituationFilter) (total, used int, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for id, s := range r.situations {
+		if !r.matches(id, filter) {
+			continue
+		}
+		total++
+		if s.IsUsed {
+			used++
+		}
+	}
+	return total, used, nil
+}
+
+func (r *SituationRepository) DeleteAll(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := len(r.situations)
+	r.situations = make(map[int]*domain.Situation)
+	r.photos = make(map[int][]domain.Photo)
+	r.decks = make(map[int][]int)
+	return count, nil
+}
+
+// matches проверяет, подходит ли ситуация под фильтр; вызывать под блокировкой
+func (r *SituationRepository) matches(id int, filter domain.SituationFilter) bool {
+	if len(filter.Difficulties) > 0 && !slices.Contains(filter.Difficulties, r.situations[id].Difficulty) {
+		return false
+	}
+	if len(filter.DeckIDs) == 0 {
+		return true
+	}
+	for _, deckID := range r.decks[id] {
+		if slices.Contains(filter.DeckIDs, deckID) {
+			return true
+		}
+	}
+	return false
+}
+
+// findPhoto ищет фото по ID; вызывать под блокировкой
+func (r *SituationRepository) findPhoto(photoID int) *domain.Photo {
+	for situationID := range r.photos {
+		photos := r.photos[situationID]
+		for i := range photos {
+			if photos[i].ID == photoID {
+				return &photos[i]
+			}
+		}
+	}
+	return nil
+}
+
+// withPhotos собирает копию ситуации с фотографиями по sort_order; вызывать под блокировкой
+func (r *SituationRepository) withPhotos(id int) *domain.SituationWithPhotos {
+	photos := append([]domain.Photo(nil), r.photos[id]...)
+	sort.SliceStable(photos, func(i, j int) bool {
+		return photos[i].SortOrder < photos[j].SortOrder
+	})
+
+	situation := *r.situations[id]
+	situation.Aliases = slices.Clone(situation.Aliases)
+	situation.Hints = slices.Clone(situation.Hints)
+
+	return &domain.SituationWithPhotos{
+		Situation: situation,
+		Photos:    photos,
+	}
+}
@@ -0,0 +1,285 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+func newSituation(t *testing.T, r *SituationRepository, answer string, fileIDs ...string) int {
+	t.Helper()
+	id, err := r.Create(context.Background(), answer, 2, fileIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func photoFileIDs(photos []domain.Photo) []string {
+	ids := make([]string, len(photos))
+	for i, p := range photos {
+		ids[i] = p.FileID
+	}
+	return ids
+}
+
+func TestSituationRepositoryPhotos(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	id := newSituation(t, r, "кот", "a", "b", "c")
+
+	s, err := r.GetByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Situation.Answer != "кот" {
+		t.Errorf("answer = %q, want %q", s.Situation.Answer, "кот")
+	}
+	if got := photoFileIDs(s.Photos); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("photos = %v, want [a b c]", got)
+	}
+	if count, _ := r.CountPhotos(ctx, id); count != 3 {
+		t.Errorf("CountPhotos = %d, want 3", count)
+	}
+
+	if _, err := r.AddPhoto(ctx, id+1, "d"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("AddPhoto to missing situation: err = %v, want ErrNotFound", err)
+	}
+	if _, err := r.GetByID(ctx, id+1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID of missing situation: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSituationRepositoryAddPhotos(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	id := newSituation(t, r, "кот", "a")
+
+	ids, err := r.AddPhotos(ctx, id, []string{"b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("AddPhotos returned %d ids, want 2", len(ids))
+	}
+
+	s, err := r.GetByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := photoFileIDs(s.Photos); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("photos = %v, want [a b c]", got)
+	}
+
+	if _, err := r.AddPhotos(ctx, id+1, []string{"d"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("AddPhotos to missing situation: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSituationRepositoryReorderPhotos(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	id := newSituation(t, r, "кот", "a", "b", "c")
+
+	s, _ := r.GetByID(ctx, id)
+	order := []int{s.Photos[2].ID, s.Photos[0].ID, s.Photos[1].ID}
+	if err := r.ReorderPhotos(ctx, id, order); err != nil {
+		t.Fatal(err)
+	}
+
+	s, _ = r.GetByID(ctx, id)
+	if got := photoFileIDs(s.Photos); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("photos = %v, want [c a b]", got)
+	}
+
+	if err := r.ReorderPhotos(ctx, id, order[:2]); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("ReorderPhotos with missing photo: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSituationRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	id := newSituation(t, r, "кот")
+
+	answer := "Кот в сапогах"
+	aliases := []string{"кот"}
+	hints := []string{"сказка", "Перро"}
+	difficulty := 3
+	decks := []int{2, 1}
+	used := true
+	err := r.Update(ctx, id, domain.SituationUpdate{
+		Answer:     &answer,
+		Aliases:    &aliases,
+		Hints:      &hints,
+		Difficulty: &difficulty,
+		DeckIDs:    &decks,
+		IsUsed:     &used,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, _ := r.GetByID(ctx, id)
+	got := s.Situation
+	if got.Answer != answer || !slices.Equal(got.Aliases, aliases) || !slices.Equal(got.Hints, hints) ||
+		got.Difficulty != difficulty || got.IsUsed != used {
+		t.Errorf("situation = %+v", got)
+	}
+	if deckIDs, _ := r.GetDeckIDs(ctx, id); !slices.Equal(deckIDs, []int{1, 2}) {
+		t.Errorf("decks = %v, want [1 2]", deckIDs)
+	}
+
+	// Поля без изменений остаются прежними
+	empty := []string{}
+	if err := r.Update(ctx, id, domain.SituationUpdate{Hints: &empty}); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = r.GetByID(ctx, id)
+	if s.Situation.Answer != answer || len(s.Situation.Hints) != 0 || s.Situation.Difficulty != difficulty {
+		t.Errorf("situation after partial update = %+v", s.Situation)
+	}
+
+	if err := r.Update(ctx, id+1, domain.SituationUpdate{Answer: &answer}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Update of missing situation: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSituationRepositoryGetRandomUnused(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	first := newSituation(t, r, "кот", "a")
+	second := newSituation(t, r, "собака", "b")
+
+	if err := r.MarkAsUsed(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	s, err := r.GetRandomUnused(ctx, domain.SituationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Situation.ID != second {
+		t.Errorf("GetRandomUnused = %d, want %d", s.Situation.ID, second)
+	}
+
+	if err := r.MarkAsUsed(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetRandomUnused(ctx, domain.SituationFilter{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetRandomUnused after all used: err = %v, want ErrNotFound", err)
+	}
+
+	total, used, _ := r.GetStats(ctx, domain.SituationFilter{})
+	if total != 2 || used != 2 {
+		t.Errorf("GetStats = %d, %d, want 2, 2", total, used)
+	}
+
+	if err := r.ResetAllUsed(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, used, _ := r.GetStats(ctx, domain.SituationFilter{}); used != 0 {
+		t.Errorf("used after reset = %d, want 0", used)
+	}
+}
+
+func TestSituationRepositoryFilter(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	animals := newSituation(t, r, "кот", "a")
+	newSituation(t, r, "дом", "b")
+	if err := r.SetDecks(ctx, animals, []int{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := domain.SituationFilter{DeckIDs: []int{1, 2}}
+	s, err := r.GetRandomUnused(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Situation.ID != animals {
+		t.Errorf("GetRandomUnused(decks 1, 2) = %d, want %d", s.Situation.ID, animals)
+	}
+	if total, _, _ := r.GetStats(ctx, filter); total != 1 {
+		t.Errorf("GetStats(decks 1, 2) total = %d, want 1", total)
+	}
+
+	if _, err := r.GetRandomUnused(ctx, domain.SituationFilter{DeckIDs: []int{2}}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetRandomUnused(empty deck): err = %v, want ErrNotFound", err)
+	}
+
+	hard, err := r.Create(ctx, "собака", 3, []string{"c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = r.GetRandomUnused(ctx, domain.SituationFilter{Difficulties: []int{3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Situation.ID != hard {
+		t.Errorf("GetRandomUnused(difficulty 3) = %d, want %d", s.Situation.ID, hard)
+	}
+	if total, _, _ := r.GetStats(ctx, domain.SituationFilter{Difficulties: []int{1, 3}}); total != 1 {
+		t.Errorf("GetStats(difficulty 1, 3) total = %d, want 1", total)
+	}
+}
+
+func TestSituationRepositoryFingerprints(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	id := newSituation(t, r, "кот", "a", "b")
+	s, _ := r.GetByID(ctx, id)
+	first, second := s.Photos[0].ID, s.Photos[1].ID
+
+	if err := r.SetPhotoFingerprint(ctx, first, "uniq", 42); err != nil {
+		t.Fatal(err)
+	}
+	// Пустой file_unique_id и нулевой хеш не затирают известные
+	if err := r.SetPhotoFingerprint(ctx, first, "", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	photos, _ := r.ListPhotoFingerprints(ctx)
+	if len(photos) != 1 || photos[0].ID != first || photos[0].FileUniqueID != "uniq" || photos[0].Hash != 42 {
+		t.Errorf("ListPhotoFingerprints = %+v", photos)
+	}
+
+	unhashed, _ := r.ListUnhashedPhotos(ctx)
+	if len(unhashed) != 1 || unhashed[0].ID != second {
+		t.Errorf("ListUnhashedPhotos = %+v, want photo %d", unhashed, second)
+	}
+}
+
+func TestSituationRepositoryDeleteAll(t *testing.T) {
+	ctx := context.Background()
+	r := NewSituationRepository()
+	first := newSituation(t, r, "кот", "a")
+	newSituation(t, r, "собака", "b")
+
+	s, _ := r.GetByID(ctx, first)
+	if err := r.SetPhotoStorage(ctx, s.Photos[0].ID, "situations/1/1.jpg", "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := r.ListStorageKeys(ctx); !slices.Equal(keys, []string{"situations/1/1.jpg"}) {
+		t.Errorf("ListStorageKeys = %v", keys)
+	}
+
+	count, err := r.DeleteAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("DeleteAll = %d, want 2", count)
+	}
+	if _, err := r.GetByID(ctx, first); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID after DeleteAll: err = %v, want ErrNotFound", err)
+	}
+	if total, _, _ := r.GetStats(ctx, domain.SituationFilter{}); total != 0 {
+		t.Errorf("total after DeleteAll = %d, want 0", total)
+	}
+	if keys, _ := r.ListStorageKeys(ctx); len(keys) != 0 {
+		t.Errorf("ListStorageKeys after DeleteAll = %v", keys)
+	}
+}
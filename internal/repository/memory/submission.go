@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.SubmissionRepository = (*SubmissionRepository)(nil)
+
+type SubmissionRepository struct {
+	submissions map[int]domain.Submission
+	nextID      int
+	mu          sync.RWMutex
+}
+
+func NewSubmissionRepository() *SubmissionRepository {
+	return &SubmissionRepository{
+		submissions: make(map[int]domain.Submission),
+		nextID:      1,
+	}
+}
+
+func (r *SubmissionRepository) Create(ctx context.Context, submission *domain.Submission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	submission.ID = r.nextID
+	submission.Status = domain.SubmissionPending
+	submission.CreatedAt = time.Now()
+	r.nextID++
+
+	r.submissions[submission.ID] = cloneSubmission(*submission)
+	return nil
+}
+
+func (r *SubmissionRepository) Get(ctx context.Context, id int) (*domain.Submission, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.submissions[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	s = cloneSubmission(s)
+	return &s, nil
+}
+
+func (r *SubmissionRepository) ListPending(ctx context.Context) ([]domain.Submission, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var submissions []domain.Submission
+	for _, s := range r.submissions {
+		if s.Status == domain.SubmissionPending {
+			submissions = append(submissions, cloneSubmission(s))
+		}
+	}
+	sort.Slice(submissions, func(i, j int) bool {
+		return submissions[i].ID < submissions[j].ID
+	})
+	return submissions, nil
+}
+
+func (r *SubmissionRepository) UpdateAnswer(ctx context.Context, id int, answer string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.submissions[id]
+	if !ok || s.Status != domain.SubmissionPending {
+		return domain.ErrNotFound
+	}
+	s.Answer = answer
+	r.submissions[id] = s
+	return nil
+}
+
+func (r *SubmissionRepository) Review(ctx context.Context, id int, status string, reviewedBy int64, situationID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.submissions[id]
+	if !ok || s.Status != domain.SubmissionPending {
+		return domain.ErrNotFound
+	}
+	s.Status = status
+	s.ReviewedBy = reviewedBy
+	s.SituationID = situationID
+	r.submissions[id] = s
+	return nil
+}
+
+func cloneSubmission(s domain.Submission) domain.Submission {
+	s.PhotoFileIDs = slices.Clone(s.PhotoFileIDs)
+	s.PhotoUniqueIDs = slices.Clone(s.PhotoUniqueIDs)
+	return s
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	users map[int64]domain.User
+	mu    sync.RWMutex
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users: make(map[int64]domain.User),
+	}
+}
+
+func (r *UserRepository) Get(ctx context.Context, telegramID int64) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[telegramID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.users[user.TelegramID]; ok {
+		if user.Name == "" {
+			user.Name = old.Name
+		}
+		user.CreatedAt = old.CreatedAt
+	} else {
+		user.CreatedAt = time.Now()
+	}
+	r.users[user.TelegramID] = *user
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, telegramID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[telegramID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.users, telegramID)
+	return nil
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].TelegramID < users[j].TelegramID
+	})
+	return users, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.ChatScoreRepository = (*ChatScoreRepository)(nil)
+
+type ChatScoreRepository struct {
+	db *DB
+}
+
+func NewChatScoreRepository(db *DB) *ChatScoreRepository {
+	return &ChatScoreRepository{db: db}
+}
+
+func (r *ChatScoreRepository) Add(ctx context.Context, chatID, userID int64, name string, score float64, correct, wrong int) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`INSERT INTO chat_scores (chat_id, user_id, name, score, correct, wrong) VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (chat_id, user_id) DO UPDATE SET
+		     name = EXCLUDED.name,
+		     score = chat_scores.score + EXCLUDED.score,
+		     correct = chat_scores.correct + EXCLUDED.correct,
+		     wrong = chat_scores.wrong + EXCLUDED.wrong,
+		     updated_at = CURRENT_TIMESTAMP`,
+		chatID, userID, name, score, correct, wrong,
+	)
+	if err != nil {
+		return fmt.Errorf("add chat score: %w", err)
+	}
+	return nil
+}
+
+func (r *ChatScoreRepository) List(ctx context.Context, chatID int64) ([]domain.ChatPlayerScore, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT user_id, name, score, correct, wrong FROM chat_scores
+		 WHERE chat_id = $1
+		 ORDER BY score DESC, correct DESC, user_id`,
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list chat scores: %w", err)
+	}
+	defer rows.Close()
+
+	var scores []domain.ChatPlayerScore
+	for rows.Next() {
+		s := domain.ChatPlayerScore{ChatID: chatID}
+		if err := rows.Scan(&s.UserID, &s.Name, &s.Score, &s.Correct, &s.Wrong); err != nil {
+			return nil, fmt.Errorf("scan chat score: %w", err)
+		}
+		scores = append(scores, s)
+	}
+
+	return scores, rows.Err()
+}
+
+func (r *ChatScoreRepository) Reset(ctx context.Context, chatID int64) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM chat_scores WHERE chat_id = $1`, chatID); err != nil {
+		return fmt.Errorf("reset chat scores: %w", err)
+	}
+	return nil
+}
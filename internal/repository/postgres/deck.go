@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.DeckRepository = (*DeckRepository)(nil)
+
+// uniqueViolation — код ошибки Postgres при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+// foreignKeyViolation — код ошибки Postgres, когда внешний ключ ссылается на несуществующую строку
+const foreignKeyViolation = "23503"
+
+type DeckRepository struct {
+	db *DB
+}
+
+func NewDeckRepository(db *DB) *DeckRepository {
+	return &DeckRepository{db: db}
+}
+
+func (r *DeckRepository) Create(ctx context.Context, name, description string) (*domain.Deck, error) {
+	d := domain.Deck{Name: name, Description: description}
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO decks (name, description) VALUES ($1, $2) RETURNING id, created_at`,
+		name, description,
+	).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, domain.ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("create deck: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *DeckRepository) List(ctx context.Context) ([]domain.Deck, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, name, description, created_at FROM decks ORDER BY name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list decks: %w", err)
+	}
+	defer rows.Close()
+
+	var decks []domain.Deck
+	for rows.Next() {
+		var d domain.Deck
+		if err := rows.Scan(&d.ID, &d.Name, &d.Description, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan deck: %w", err)
+		}
+		decks = append(decks, d)
+	}
+
+	return decks, rows.Err()
+}
+
+// GetByName ищет колоду по названию без учёта регистра
+func (r *DeckRepository) GetByName(ctx context.Context, name string) (*domain.Deck, error) {
+	var d domain.Deck
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT id, name, description, created_at FROM decks WHERE LOWER(name) = LOWER($1)`,
+		name,
+	).Scan(&d.ID, &d.Name, &d.Description, &d.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get deck by name: %w", err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.DraftRepository = (*DraftRepository)(nil)
+
+type DraftRepository struct {
+	db *DB
+}
+
+func NewDraftRepository(db *DB) *DraftRepository {
+	return &DraftRepository{db: db}
+}
+
+func (r *DraftRepository) Get(ctx context.Context, userID int64, kind string) (*domain.Draft, error) {
+	d := domain.Draft{UserID: userID, Kind: kind}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT data, updated_at FROM drafts WHERE user_id = $1 AND kind = $2`,
+		userID, kind,
+	).Scan(&d.Data, &d.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get draft: %w", err)
+	}
+	return &d, nil
+}
+
+// Save создаёт или заменяет черновик; время изменения ставит база
+func (r *DraftRepository) Save(ctx context.Context, draft *domain.Draft) error {
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO drafts (user_id, kind, data) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, kind) DO UPDATE SET data = EXCLUDED.data, updated_at = CURRENT_TIMESTAMP
+		 RETURNING updated_at`,
+		draft.UserID, draft.Kind, draft.Data,
+	).Scan(&draft.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	return nil
+}
+
+func (r *DraftRepository) Delete(ctx context.Context, userID int64, kind string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM drafts WHERE user_id = $1 AND kind = $2`, userID, kind)
+	if err != nil {
+		return fmt.Errorf("delete draft: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *DraftRepository) List(ctx context.Context, kind string) ([]domain.Draft, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT user_id, data, updated_at FROM drafts WHERE kind = $1 ORDER BY updated_at, user_id`,
+		kind,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list drafts: %w", err)
+	}
+	defer rows.Close()
+
+	var drafts []domain.Draft
+	for rows.Next() {
+		d := domain.Draft{Kind: kind}
+		if err := rows.Scan(&d.UserID, &d.Data, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan draft: %w", err)
+		}
+		drafts = append(drafts, d)
+	}
+
+	return drafts, rows.Err()
+}
+
+// DeleteOlder удаляет черновики, которые не менялись с момента before
+func (r *DraftRepository) DeleteOlder(ctx context.Context, before time.Time) (int, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM drafts WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete old drafts: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
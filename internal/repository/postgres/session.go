@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.SessionRepository = (*SessionRepository)(nil)
+
+type SessionRepository struct {
+	db *DB
+}
+
+func NewSessionRepository(db *DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *domain.GameSession) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO game_sessions (id, code, current_player_id, current_round, is_active, is_finished, deck_ids, difficulties, balanced, photo_seconds, score_rule, rotate_members, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		session.ID, session.Code, session.CurrentPlayerID, session.CurrentRound, session.IsActive, session.IsFinished,
+		intArray(session.DeckIDs), intArray(session.Difficulties), session.Balanced, session.PhotoSeconds, floatArray(session.ScoreRule), session.RotateMembers, session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+
+	for _, t := range session.Teams {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO session_teams (id, session_id, name, sort_order) VALUES ($1, $2, $3, $4)`,
+			t.ID, session.ID, t.Name, t.Order,
+		)
+		if err != nil {
+			return fmt.Errorf("create team: %w", err)
+		}
+	}
+
+	for _, p := range session.Players {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO session_players (id, session_id, name, sort_order, team_id) VALUES ($1, $2, $3, $4, NULLIF($5, ''))`,
+			p.ID, session.ID, p.Name, p.Order, p.TeamID,
+		)
+		if err != nil {
+			return fmt.Errorf("create player: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// ListActive возвращает все незавершённые сессии вместе с игроками и их очками
+func (r *SessionRepository) ListActive(ctx context.Context) ([]*domain.GameSession, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, code, current_player_id, current_round, is_active, is_finished, deck_ids, difficulties, balanced, photo_seconds, score_rule, rotate_members, created_at
+		 FROM game_sessions
+		 WHERE is_active = TRUE
+		 ORDER BY created_at`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list active sessions: %w", err)
+	}
+
+	var sessions []*domain.GameSession
+	for rows.Next() {
+		var s domain.GameSession
+		if err := rows.Scan(&s.ID, &s.Code, &s.CurrentPlayerID, &s.CurrentRound, &s.IsActive, &s.IsFinished, &s.DeckIDs, &s.Difficulties, &s.Balanced, &s.PhotoSeconds, &s.ScoreRule, &s.RotateMembers, &s.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		sessions = append(sessions, &s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list active sessions: %w", err)
+	}
+
+	for _, s := range sessions {
+		players, err := r.getPlayersBySessionID(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		s.Players = players
+
+		teams, err := r.getTeamsBySessionID(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		s.Teams = teams
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) AddScore(ctx context.Context, sessionID, playerID string, round int, score float64) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`INSERT INTO score_entries (session_id, player_id, round, score) VALUES ($1, $2, $3, $4)`,
+		sessionID, playerID, round, score,
+	)
+	if err != nil {
+		return fmt.Errorf("add score: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) UpdateTurn(ctx context.Context, sessionID, currentPlayerID string, round int) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE game_sessions SET current_player_id = $2, current_round = $3 WHERE id = $1`,
+		sessionID, currentPlayerID, round,
+	)
+	if err != nil {
+		return fmt.Errorf("update turn: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) Finish(ctx context.Context, sessionID string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE game_sessions
+		 SET is_active = FALSE, is_finished = TRUE, finished_at = CURRENT_TIMESTAMP
+		 WHERE id = $1`,
+		sessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("finish session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) getPlayersBySessionID(ctx context.Context, sessionID string) ([]domain.Player, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT p.id, p.name, p.sort_order, COALESCE(p.team_id, ''), COALESCE(SUM(e.score), 0)
+		 FROM session_players p
+		 LEFT JOIN score_entries e ON e.player_id = p.id
+		 WHERE p.session_id = $1
+		 GROUP BY p.id, p.name, p.sort_order, p.team_id
+		 ORDER BY p.sort_order`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get players: %w", err)
+	}
+	defer rows.Close()
+
+	var players []domain.Player
+	for rows.Next() {
+		var p domain.Player
+		if err := rows.Scan(&p.ID, &p.Name, &p.Order, &p.TeamID, &p.Score); err != nil {
+			return nil, fmt.Errorf("scan player: %w", err)
+		}
+		players = append(players, p)
+	}
+
+	return players, rows.Err()
+}
+
+func (r *SessionRepository) getTeamsBySessionID(ctx context.Context, sessionID string) ([]domain.Team, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, name, sort_order FROM session_teams WHERE session_id = $1 ORDER BY sort_order`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []domain.Team
+	for rows.Next() {
+		var t domain.Team
+		if err := rows.Scan(&t.ID, &t.Name, &t.Order); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		teams = append(teams, t)
+	}
+
+	return teams, rows.Err()
+}
+
+// intArray заменяет nil пустым списком для колонок INTEGER[] NOT NULL
+func intArray(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
+
+// floatArray заменяет nil пустым списком для колонок DOUBLE PRECISION[] NOT NULL
+func floatArray(values []float64) []float64 {
+	if values == nil {
+		return []float64{}
+	}
+	return values
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/plastinin/photo-quiz-bot/internal/domain"
 )
 
-var ErrNotFound = errors.New("not found")
+var ErrNotFound = domain.ErrNotFound
+
+var _ domain.SituationRepository = (*SituationRepository)(nil)
 
 type SituationRepository struct {
 	db *DB
@@ -19,11 +24,11 @@ func NewSituationRepository(db *DB) *SituationRepository {
 	return &SituationRepository{db: db}
 }
 
-func (r *SituationRepository) CreateSituation(ctx context.Context, answer string) (int, error) {
+func (r *SituationRepository) CreateSituation(ctx context.Context, answer string, difficulty int) (int, error) {
 	var id int
 	err := r.db.Pool.QueryRow(ctx,
-		`INSERT INTO situations (answer) VALUES ($1) RETURNING id`,
-		answer,
+		`INSERT INTO situations (answer, answer_norm, difficulty) VALUES ($1, $2, $3) RETURNING id`,
+		answer, domain.NormalizeAnswer(answer), difficulty,
 	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("create situation: %w", err)
@@ -31,54 +36,154 @@ func (r *SituationRepository) CreateSituation(ctx context.Context, answer string
 	return id, nil
 }
 
-func (r *SituationRepository) AddPhoto(ctx context.Context, situationID int, fileID string) error {
-	
+func (r *SituationRepository) AddPhoto(ctx context.Context, situationID int, fileID string) (int, error) {
 	var sortOrder int
 	err := r.db.Pool.QueryRow(ctx,
 		`SELECT COALESCE(MAX(sort_order), -1) + 1 FROM photos WHERE situation_id = $1`,
 		situationID,
 	).Scan(&sortOrder)
 	if err != nil {
-		return fmt.Errorf("get sort order: %w", err)
+		return 0, fmt.Errorf("get sort order: %w", err)
 	}
 
-	_, err = r.db.Pool.Exec(ctx,
-		`INSERT INTO photos (situation_id, file_id, sort_order) VALUES ($1, $2, $3)`,
+	var photoID int
+	err = r.db.Pool.QueryRow(ctx,
+		`INSERT INTO photos (situation_id, file_id, sort_order) VALUES ($1, $2, $3) RETURNING id`,
 		situationID, fileID, sortOrder,
-	)
+	).Scan(&photoID)
 	if err != nil {
-		return fmt.Errorf("add photo: %w", err)
+		return 0, addPhotoError(err)
 	}
-	return nil
+	return photoID, nil
 }
 
-func (r *SituationRepository) Create(ctx context.Context, answer string, photoFileIDs []string) error {
+// AddPhotos добавляет фото в конец ситуации в одной транзакции: либо все, либо ни одного
+func (r *SituationRepository) AddPhotos(ctx context.Context, situationID int, fileIDs []string) ([]int, error) {
+	ids := make([]int, 0, len(fileIDs))
+
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		var sortOrder int
+		err := tx.QueryRow(ctx,
+			`SELECT COALESCE(MAX(sort_order), -1) + 1 FROM photos WHERE situation_id = $1`,
+			situationID,
+		).Scan(&sortOrder)
+		if err != nil {
+			return fmt.Errorf("get sort order: %w", err)
+		}
+
+		for i, fileID := range fileIDs {
+			var photoID int
+			err := tx.QueryRow(ctx,
+				`INSERT INTO photos (situation_id, file_id, sort_order) VALUES ($1, $2, $3) RETURNING id`,
+				situationID, fileID, sortOrder+i,
+			).Scan(&photoID)
+			if err != nil {
+				return addPhotoError(err)
+			}
+			ids = append(ids, photoID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// addPhotoError превращает ссылку на несуществующую ситуацию в ErrNotFound
+func addPhotoError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return ErrNotFound
+	}
+	return fmt.Errorf("add photo: %w", err)
+}
+
+func (r *SituationRepository) Create(ctx context.Context, answer string, difficulty int, photoFileIDs []string) (int, error) {
 	// Создаём ситуацию
-	situationID, err := r.CreateSituation(ctx, answer)
+	situationID, err := r.CreateSituation(ctx, answer, difficulty)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Добавляем фотографии
 	for _, fileID := range photoFileIDs {
-		if err := r.AddPhoto(ctx, situationID, fileID); err != nil {
-			return err
+		if _, err := r.AddPhoto(ctx, situationID, fileID); err != nil {
+			return 0, err
 		}
 	}
 
-	return nil
+	return situationID, nil
 }
 
-func (r *SituationRepository) GetRandomUnused(ctx context.Context) (*domain.SituationWithPhotos, error) {
-	
+// CreateBatch создаёт ситуации с фото и колодами в одной транзакции: либо все, либо ни одной
+func (r *SituationRepository) CreateBatch(ctx context.Context, situations []domain.NewSituation) ([]int, error) {
+	ids := make([]int, 0, len(situations))
+
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		for _, s := range situations {
+			var id int
+			aliases := s.Aliases
+			if aliases == nil {
+				aliases = []string{}
+			}
+			hints := s.Hints
+			if hints == nil {
+				hints = []string{}
+			}
+
+			err := tx.QueryRow(ctx,
+				`INSERT INTO situations (answer, answer_norm, aliases, hints, difficulty, is_used) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+				s.Answer, domain.NormalizeAnswer(s.Answer), aliases, hints, s.Difficulty, s.IsUsed,
+			).Scan(&id)
+			if err != nil {
+				return fmt.Errorf("create situation %q: %w", s.Answer, err)
+			}
+
+			for i, fileID := range s.PhotoFileIDs {
+				_, err := tx.Exec(ctx,
+					`INSERT INTO photos (situation_id, file_id, sort_order) VALUES ($1, $2, $3)`,
+					id, fileID, i,
+				)
+				if err != nil {
+					return fmt.Errorf("add photo: %w", err)
+				}
+			}
+
+			for _, deckID := range s.DeckIDs {
+				_, err := tx.Exec(ctx,
+					`INSERT INTO situation_decks (situation_id, deck_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+					id, deckID,
+				)
+				if err != nil {
+					return fmt.Errorf("add situation to deck: %w", err)
+				}
+			}
+
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *SituationRepository) GetRandomUnused(ctx context.Context, filter domain.SituationFilter) (*domain.SituationWithPhotos, error) {
+	where, args := situationFilterSQL(filter, nil)
+
 	var s domain.Situation
 	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, answer, is_used, created_at 
+		`SELECT id, answer, aliases, hints, difficulty, is_used, created_at 
 		 FROM situations 
-		 WHERE is_used = FALSE 
+		 WHERE is_used = FALSE`+where+`
 		 ORDER BY RANDOM() 
 		 LIMIT 1`,
-	).Scan(&s.ID, &s.Answer, &s.IsUsed, &s.CreatedAt)
+		args...,
+	).Scan(&s.ID, &s.Answer, &s.Aliases, &s.Hints, &s.Difficulty, &s.IsUsed, &s.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -119,9 +224,9 @@ func (r *SituationRepository) ResetAllUsed(ctx context.Context) error {
 func (r *SituationRepository) GetByID(ctx context.Context, id int) (*domain.SituationWithPhotos, error) {
 	var s domain.Situation
 	err := r.db.Pool.QueryRow(ctx,
-		`SELECT id, answer, is_used, created_at FROM situations WHERE id = $1`,
+		`SELECT id, answer, aliases, hints, difficulty, is_used, created_at FROM situations WHERE id = $1`,
 		id,
-	).Scan(&s.ID, &s.Answer, &s.IsUsed, &s.CreatedAt)
+	).Scan(&s.ID, &s.Answer, &s.Aliases, &s.Hints, &s.Difficulty, &s.IsUsed, &s.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -140,6 +245,186 @@ func (r *SituationRepository) GetByID(ctx context.Context, id int) (*domain.Situ
 	}, nil
 }
 
+// List возвращает страницу ситуаций по возрастанию ID и общее их число
+func (r *SituationRepository) List(ctx context.Context, offset, limit int) ([]domain.SituationSummary, int, error) {
+	return r.Search(ctx, "", offset, limit)
+}
+
+// Search — как List, но только ситуации, в ответе которых есть query (без учёта регистра),
+// или ситуация с номером query
+func (r *SituationRepository) Search(ctx context.Context, query string, offset, limit int) ([]domain.SituationSummary, int, error) {
+	where := ""
+	args := []any{}
+	if query = strings.TrimSpace(query); query != "" {
+		id, _ := strconv.Atoi(strings.TrimPrefix(query, "#"))
+		args = append(args, "%"+escapeLike(query)+"%", id)
+		where = ` WHERE s.answer ILIKE $1 OR s.id = $2`
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM situations s`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count situations: %w", err)
+	}
+
+	args = append(args, offset, limit)
+	rows, err := r.db.Pool.Query(ctx,
+		fmt.Sprintf(`SELECT s.id, s.answer, s.difficulty, s.is_used, s.created_at, COUNT(p.id)
+		 FROM situations s
+		 LEFT JOIN photos p ON p.situation_id = s.id%s
+		 GROUP BY s.id
+		 ORDER BY s.id
+		 OFFSET $%d LIMIT $%d`, where, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list situations: %w", err)
+	}
+	defer rows.Close()
+
+	var situations []domain.SituationSummary
+	for rows.Next() {
+		var s domain.SituationSummary
+		if err := rows.Scan(&s.ID, &s.Answer, &s.Difficulty, &s.IsUsed, &s.CreatedAt, &s.PhotoCount); err != nil {
+			return nil, 0, fmt.Errorf("scan situation: %w", err)
+		}
+		situations = append(situations, s)
+	}
+
+	return situations, total, rows.Err()
+}
+
+func (r *SituationRepository) UpdateAnswer(ctx context.Context, id int, answer string) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE situations SET answer = $2, answer_norm = $3 WHERE id = $1`, id, answer, domain.NormalizeAnswer(answer))
+	if err != nil {
+		return fmt.Errorf("update answer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SituationRepository) SetAliases(ctx context.Context, id int, aliases []string) error {
+	if aliases == nil {
+		aliases = []string{}
+	}
+
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE situations SET aliases = $2 WHERE id = $1`, id, aliases)
+	if err != nil {
+		return fmt.Errorf("set aliases: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SituationRepository) SetHints(ctx context.Context, id int, hints []string) error {
+	if hints == nil {
+		hints = []string{}
+	}
+
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE situations SET hints = $2 WHERE id = $1`, id, hints)
+	if err != nil {
+		return fmt.Errorf("set hints: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SituationRepository) UpdateDifficulty(ctx context.Context, id int, difficulty int) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE situations SET difficulty = $2 WHERE id = $1`, id, difficulty)
+	if err != nil {
+		return fmt.Errorf("update difficulty: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SituationRepository) SetUsed(ctx context.Context, id int, used bool) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE situations SET is_used = $2 WHERE id = $1`, id, used)
+	if err != nil {
+		return fmt.Errorf("set used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Update сохраняет изменения ситуации в одной транзакции: либо все, либо ни одного
+func (r *SituationRepository) Update(ctx context.Context, id int, update domain.SituationUpdate) error {
+	var answer, answerNorm *string
+	if update.Answer != nil {
+		norm := domain.NormalizeAnswer(*update.Answer)
+		answer, answerNorm = update.Answer, &norm
+	}
+
+	return pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE situations SET
+			   answer = COALESCE($2, answer),
+			   answer_norm = COALESCE($3, answer_norm),
+			   aliases = COALESCE($4, aliases),
+			   hints = COALESCE($5, hints),
+			   difficulty = COALESCE($6, difficulty),
+			   is_used = COALESCE($7, is_used)
+			 WHERE id = $1`,
+			id, answer, answerNorm, textArray(update.Aliases), textArray(update.Hints), update.Difficulty, update.IsUsed,
+		)
+		if err != nil {
+			return fmt.Errorf("update situation: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+
+		if update.DeckIDs == nil {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM situation_decks WHERE situation_id = $1`, id); err != nil {
+			return fmt.Errorf("clear situation decks: %w", err)
+		}
+		for _, deckID := range *update.DeckIDs {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO situation_decks (situation_id, deck_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+				id, deckID,
+			)
+			if err != nil {
+				return fmt.Errorf("add situation to deck: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// textArray готовит необязательный список для COALESCE: nil — не менять, пустой список — очистить
+func textArray(values *[]string) any {
+	if values == nil {
+		return nil
+	}
+	if *values == nil {
+		return []string{}
+	}
+	return *values
+}
+
+// Delete удаляет ситуацию; фото и привязки к колодам удаляются каскадно
+func (r *SituationRepository) Delete(ctx context.Context, id int) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM situations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete situation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (r *SituationRepository) CountPhotos(ctx context.Context, situationID int) (int, error) {
 	var count int
 	err := r.db.Pool.QueryRow(ctx,
@@ -152,9 +437,234 @@ func (r *SituationRepository) CountPhotos(ctx context.Context, situationID int)
 	return count, nil
 }
 
-func (r *SituationRepository) GetStats(ctx context.Context) (total, used int, err error) {
+func (r *SituationRepository) GetPhotoByID(ctx context.Context, photoID int) (*domain.Photo, error) {
+	var p domain.Photo
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT id, situation_id, file_id, sort_order, COALESCE(storage_key, ''), COALESCE(content_type, ''), created_at
+		 FROM photos WHERE id = $1`,
+		photoID,
+	).Scan(&p.ID, &p.SituationID, &p.FileID, &p.SortOrder, &p.StorageKey, &p.ContentType, &p.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get photo by id: %w", err)
+	}
+	p.OrderNum = p.SortOrder
+	return &p, nil
+}
+
+func (r *SituationRepository) SetPhotoStorage(ctx context.Context, photoID int, storageKey, contentType string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE photos SET storage_key = $2, content_type = $3 WHERE id = $1`,
+		photoID, storageKey, contentType,
+	)
+	if err != nil {
+		return fmt.Errorf("set photo storage: %w", err)
+	}
+	return nil
+}
+
+// SetPhotoFingerprint запоминает отпечаток фото; пустой file_unique_id и нулевой хеш не затирают известные
+func (r *SituationRepository) SetPhotoFingerprint(ctx context.Context, photoID int, fileUniqueID string, hash uint64) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE photos SET file_unique_id = COALESCE(NULLIF($2, ''), file_unique_id), phash = COALESCE(NULLIF($3, 0), phash)
+		 WHERE id = $1`,
+		photoID, fileUniqueID, int64(hash),
+	)
+	if err != nil {
+		return fmt.Errorf("set photo fingerprint: %w", err)
+	}
+	return nil
+}
+
+// ListPhotoFingerprints возвращает фото, у которых известен хоть какой-то отпечаток
+func (r *SituationRepository) ListPhotoFingerprints(ctx context.Context) ([]domain.Photo, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, situation_id, file_unique_id, COALESCE(phash, 0) FROM photos
+		 WHERE file_unique_id <> '' OR phash IS NOT NULL
+		 ORDER BY situation_id, sort_order, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list photo fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []domain.Photo
+	for rows.Next() {
+		var p domain.Photo
+		var hash int64
+		if err := rows.Scan(&p.ID, &p.SituationID, &p.FileUniqueID, &hash); err != nil {
+			return nil, fmt.Errorf("scan photo fingerprint: %w", err)
+		}
+		p.Hash = uint64(hash)
+		photos = append(photos, p)
+	}
+
+	return photos, rows.Err()
+}
+
+// ListUnhashedPhotos возвращает фото, для которых ещё не посчитан перцептивный хеш
+func (r *SituationRepository) ListUnhashedPhotos(ctx context.Context) ([]domain.Photo, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, situation_id, file_id, sort_order, COALESCE(storage_key, ''), COALESCE(content_type, ''), file_unique_id
+		 FROM photos WHERE phash IS NULL
+		 ORDER BY situation_id, sort_order, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list unhashed photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []domain.Photo
+	for rows.Next() {
+		var p domain.Photo
+		if err := rows.Scan(&p.ID, &p.SituationID, &p.FileID, &p.SortOrder, &p.StorageKey, &p.ContentType, &p.FileUniqueID); err != nil {
+			return nil, fmt.Errorf("scan unhashed photo: %w", err)
+		}
+		p.OrderNum = p.SortOrder
+		photos = append(photos, p)
+	}
+
+	return photos, rows.Err()
+}
+
+// ListStorageKeys возвращает ключи всех фото, сохранённых в хранилище
+func (r *SituationRepository) ListStorageKeys(ctx context.Context) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT storage_key FROM photos WHERE storage_key <> '' ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list storage keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan storage key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// FindByAnswer возвращает ID ситуаций с таким нормализованным ответом
+func (r *SituationRepository) FindByAnswer(ctx context.Context, normalized string) ([]int, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT id FROM situations WHERE answer_norm = $1 ORDER BY id`, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("find situations by answer: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan situation id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// SetDecks заменяет набор колод, в которые входит ситуация
+func (r *SituationRepository) SetDecks(ctx context.Context, situationID int, deckIDs []int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM situation_decks WHERE situation_id = $1`, situationID); err != nil {
+		return fmt.Errorf("clear situation decks: %w", err)
+	}
+
+	for _, deckID := range deckIDs {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO situation_decks (situation_id, deck_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			situationID, deckID,
+		)
+		if err != nil {
+			return fmt.Errorf("add situation to deck: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// ReorderPhotos задаёт порядок показа фото ситуации: photoIDs — все её фото в нужном порядке
+func (r *SituationRepository) ReorderPhotos(ctx context.Context, situationID int, photoIDs []int) error {
+	return pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM photos WHERE situation_id = $1`, situationID).Scan(&count); err != nil {
+			return fmt.Errorf("count photos: %w", err)
+		}
+		if count != len(photoIDs) {
+			return ErrNotFound
+		}
+
+		for i, photoID := range photoIDs {
+			tag, err := tx.Exec(ctx,
+				`UPDATE photos SET sort_order = $3 WHERE id = $1 AND situation_id = $2`,
+				photoID, situationID, i,
+			)
+			if err != nil {
+				return fmt.Errorf("reorder photos: %w", err)
+			}
+			if tag.RowsAffected() == 0 {
+				return ErrNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// GetDeckIDs возвращает колоды, в которые входит ситуация
+func (r *SituationRepository) GetDeckIDs(ctx context.Context, situationID int) ([]int, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT deck_id FROM situation_decks WHERE situation_id = $1 ORDER BY deck_id`,
+		situationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get situation decks: %w", err)
+	}
+
+	defer rows.Close()
+
+	var deckIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan situation deck: %w", err)
+		}
+		deckIDs = append(deckIDs, id)
+	}
+
+	return deckIDs, rows.Err()
+}
+
+func (r *SituationRepository) DeletePhoto(ctx context.Context, photoID int) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM photos WHERE id = $1`, photoID)
+	if err != nil {
+		return fmt.Errorf("delete photo: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SituationRepository) GetStats(ctx context.Context, filter domain.SituationFilter) (total, used int, err error) {
+	where, args := situationFilterSQL(filter, nil)
+
 	err = r.db.Pool.QueryRow(ctx,
-		`SELECT COUNT(*), COUNT(*) FILTER (WHERE is_used = TRUE) FROM situations`,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE is_used = TRUE) FROM situations WHERE TRUE`+where,
+		args...,
 	).Scan(&total, &used)
 	if err != nil {
 		return 0, 0, fmt.Errorf("get stats: %w", err)
@@ -164,7 +674,7 @@ func (r *SituationRepository) GetStats(ctx context.Context) (total, used int, er
 
 func (r *SituationRepository) getPhotosBySituationID(ctx context.Context, situationID int) ([]domain.Photo, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT id, situation_id, file_id, sort_order, created_at 
+		`SELECT id, situation_id, file_id, sort_order, COALESCE(storage_key, ''), COALESCE(content_type, ''), created_at 
 		 FROM photos 
 		 WHERE situation_id = $1 
 		 ORDER BY sort_order`,
@@ -178,7 +688,7 @@ func (r *SituationRepository) getPhotosBySituationID(ctx context.Context, situat
 	var photos []domain.Photo
 	for rows.Next() {
 		var p domain.Photo
-		if err := rows.Scan(&p.ID, &p.SituationID, &p.FileID, &p.SortOrder, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.SituationID, &p.FileID, &p.SortOrder, &p.StorageKey, &p.ContentType, &p.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan photo: %w", err)
 		}
 		p.OrderNum = p.SortOrder // для совместимости
@@ -202,4 +712,29 @@ func (r *SituationRepository) DeleteAll(ctx context.Context) (int, error) {
 	}
 
 	return count, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// situationFilterSQL возвращает условия фильтра для WHERE по таблице situations (начиная с AND)
+// и дополненный список аргументов запроса
+func situationFilterSQL(filter domain.SituationFilter, args []any) (string, []any) {
+	var where string
+
+	if len(filter.DeckIDs) > 0 {
+		args = append(args, filter.DeckIDs)
+		where += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM situation_decks sd
+			WHERE sd.situation_id = situations.id AND sd.deck_id = ANY($%d))`, len(args))
+	}
+
+	if len(filter.Difficulties) > 0 {
+		args = append(args, filter.Difficulties)
+		where += fmt.Sprintf(` AND difficulty = ANY($%d)`, len(args))
+	}
+
+	return where, args
 }
\ No newline at end of file
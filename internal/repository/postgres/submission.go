@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.SubmissionRepository = (*SubmissionRepository)(nil)
+
+type SubmissionRepository struct {
+	db *DB
+}
+
+func NewSubmissionRepository(db *DB) *SubmissionRepository {
+	return &SubmissionRepository{db: db}
+}
+
+const submissionColumns = `id, author_id, author_name, answer, difficulty, photo_file_ids, photo_unique_ids,
+	status, COALESCE(reviewed_by, 0), COALESCE(situation_id, 0), created_at`
+
+func scanSubmission(row pgx.Row, s *domain.Submission) error {
+	return row.Scan(&s.ID, &s.AuthorID, &s.AuthorName, &s.Answer, &s.Difficulty, &s.PhotoFileIDs, &s.PhotoUniqueIDs,
+		&s.Status, &s.ReviewedBy, &s.SituationID, &s.CreatedAt)
+}
+
+func (r *SubmissionRepository) Create(ctx context.Context, submission *domain.Submission) error {
+	uniqueIDs := submission.PhotoUniqueIDs
+	if uniqueIDs == nil {
+		uniqueIDs = []string{}
+	}
+
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO submissions (author_id, author_name, answer, difficulty, photo_file_ids, photo_unique_ids)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, status, created_at`,
+		submission.AuthorID, submission.AuthorName, submission.Answer, submission.Difficulty, submission.PhotoFileIDs, uniqueIDs,
+	).Scan(&submission.ID, &submission.Status, &submission.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create submission: %w", err)
+	}
+	return nil
+}
+
+func (r *SubmissionRepository) Get(ctx context.Context, id int) (*domain.Submission, error) {
+	var s domain.Submission
+	err := scanSubmission(r.db.Pool.QueryRow(ctx, `SELECT `+submissionColumns+` FROM submissions WHERE id = $1`, id), &s)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get submission: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SubmissionRepository) ListPending(ctx context.Context) ([]domain.Submission, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+submissionColumns+` FROM submissions WHERE status = $1 ORDER BY created_at, id`,
+		domain.SubmissionPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var submissions []domain.Submission
+	for rows.Next() {
+		var s domain.Submission
+		if err := scanSubmission(rows, &s); err != nil {
+			return nil, fmt.Errorf("scan submission: %w", err)
+		}
+		submissions = append(submissions, s)
+	}
+
+	return submissions, rows.Err()
+}
+
+func (r *SubmissionRepository) UpdateAnswer(ctx context.Context, id int, answer string) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE submissions SET answer = $2 WHERE id = $1 AND status = $3`,
+		id, answer, domain.SubmissionPending,
+	)
+	if err != nil {
+		return fmt.Errorf("update submission answer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SubmissionRepository) Review(ctx context.Context, id int, status string, reviewedBy int64, situationID int) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE submissions
+		 SET status = $2, reviewed_by = $3, situation_id = NULLIF($4, 0), reviewed_at = CURRENT_TIMESTAMP
+		 WHERE id = $1 AND status = $5`,
+		id, status, reviewedBy, situationID, domain.SubmissionPending,
+	)
+	if err != nil {
+		return fmt.Errorf("review submission: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var _ domain.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Get(ctx context.Context, telegramID int64) (*domain.User, error) {
+	var u domain.User
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT telegram_id, name, role, COALESCE(granted_by, 0), created_at FROM users WHERE telegram_id = $1`,
+		telegramID,
+	).Scan(&u.TelegramID, &u.Name, &u.Role, &u.GrantedBy, &u.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return &u, nil
+}
+
+// Save создаёт пользователя или меняет его роль; пустое имя не затирает сохранённое
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO users (telegram_id, name, role, granted_by) VALUES ($1, $2, $3, NULLIF($4, 0))
+		 ON CONFLICT (telegram_id) DO UPDATE
+		 SET name = COALESCE(NULLIF(EXCLUDED.name, ''), users.name), role = EXCLUDED.role, granted_by = EXCLUDED.granted_by
+		 RETURNING name, created_at`,
+		user.TelegramID, user.Name, user.Role, user.GrantedBy,
+	).Scan(&user.Name, &user.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("save user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, telegramID int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM users WHERE telegram_id = $1`, telegramID)
+	if err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT telegram_id, name, role, COALESCE(granted_by, 0), created_at FROM users ORDER BY created_at, telegram_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.TelegramID, &u.Name, &u.Role, &u.GrantedBy, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
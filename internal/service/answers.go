@@ -0,0 +1,141 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// DefaultAnswerThreshold — с какой похожести догадка засчитывается, если порог не задан
+const DefaultAnswerThreshold = 0.8
+
+// AnswerMatch — результат сравнения догадки с принятыми вариантами ответа
+type AnswerMatch struct {
+	Correct bool
+	Score   float64 // похожесть от 0 до 1
+	Matched string  // вариант ответа, на который догадка похожа больше всего
+}
+
+// AnswerMatcher сравнивает догадки игроков с ответом ситуации и его синонимами.
+// Регистр, «ё» и знаки препинания не учитываются, опечатки и порядок слов прощаются,
+// пока похожесть не ниже порога. Числа должны совпасть точно.
+type AnswerMatcher struct {
+	threshold float64
+}
+
+func NewAnswerMatcher(threshold float64) *AnswerMatcher {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultAnswerThreshold
+	}
+	return &AnswerMatcher{threshold: threshold}
+}
+
+func (m *AnswerMatcher) Threshold() float64 {
+	return m.threshold
+}
+
+// Match сравнивает догадку со всеми принятыми вариантами и возвращает лучшее совпадение
+func (m *AnswerMatcher) Match(guess string, accepted []string) AnswerMatch {
+	var best AnswerMatch
+	normalized := domain.NormalizeAnswer(guess)
+	for _, answer := range accepted {
+		score := AnswerSimilarity(normalized, domain.NormalizeAnswer(answer))
+		if score > best.Score || best.Matched == "" {
+			best = AnswerMatch{Score: score, Matched: answer}
+		}
+	}
+	best.Correct = best.Score >= m.threshold
+	return best
+}
+
+// AnswerSimilarity возвращает похожесть двух нормализованных ответов от 0 до 1:
+// лучшее из посимвольного сравнения (опечатки) и пословного (другой порядок слов,
+// пропущенное или лишнее слово)
+func AnswerSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	// «1984» и «1985» — разные ответы, хотя отличаются одной цифрой
+	if !slices.Equal(numbers(a), numbers(b)) {
+		return 0
+	}
+
+	return max(charSimilarity(a, b), tokenSimilarity(a, b))
+}
+
+// charSimilarity — доля совпадающих символов по расстоянию Левенштейна
+func charSimilarity(a, b string) float64 {
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein([]rune(a), []rune(b)))/float64(longest)
+}
+
+// tokenSimilarity сравнивает ответы по словам без учёта порядка: F-мера того,
+// насколько слова одного ответа находят похожие слова в другом
+func tokenSimilarity(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	precision := coverage(wordsA, wordsB)
+	recall := coverage(wordsB, wordsA)
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
+
+// coverage — какая доля букв слов words приходится на слова, похожие на слова others
+func coverage(words, others []string) float64 {
+	var total, matched float64
+	for _, w := range words {
+		length := float64(utf8.RuneCountInString(w))
+		best := 0.0
+		for _, o := range others {
+			best = max(best, charSimilarity(w, o))
+		}
+		total += length
+		matched += length * best
+	}
+	if total == 0 {
+		return 0
+	}
+	return matched / total
+}
+
+func numbers(s string) []string {
+	var nums []string
+	for _, w := range strings.Fields(s) {
+		if strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			nums = append(nums, w)
+		}
+	}
+	return nums
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
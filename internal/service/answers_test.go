@@ -0,0 +1,106 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnswerSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"одинаковые", "кот в сапогах", "кот в сапогах", 1},
+		{"обе пустые", "", "", 1},
+		{"пустая догадка", "", "кот", 0},
+		{"пустой ответ", "кот", "", 0},
+		{"опечатка", "масква", "москва", 1 - 1.0/6},
+		{"другой порядок слов", "в сапогах кот", "кот в сапогах", 1},
+		{"пропущено слово", "кот сапогах", "кот в сапогах", 20.0 / 21},
+		{"разные числа", "1984", "1985", 0},
+		{"разные числа в словах", "война 1812 года", "война 1813 года", 0},
+		{"лишнее число", "1984 оруэлл", "оруэлл", 0},
+		{"одинаковые числа", "1984 оруэл", "1984 оруэлл", 1 - 1.0/11},
+		{"совсем разные", "кот", "дом", 1 - 2.0/3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnswerSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("AnswerSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"те же слова", "красная шапочка", "шапочка красная", 1},
+		{"лишнее слово", "кот в сапогах", "кот сапогах", 20.0 / 21},
+		{"нет общих букв", "аб", "вг", 0},
+		{"пустая строка", "", "кот", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("tokenSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAnswerMatcherThreshold(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		want      float64
+	}{
+		{0, DefaultAnswerThreshold},
+		{-0.5, DefaultAnswerThreshold},
+		{1.5, DefaultAnswerThreshold},
+		{0.6, 0.6},
+		{1, 1},
+	}
+
+	for _, tt := range tests {
+		if got := NewAnswerMatcher(tt.threshold).Threshold(); got != tt.want {
+			t.Errorf("NewAnswerMatcher(%v).Threshold() = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestAnswerMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   float64
+		guess       string
+		accepted    []string
+		wantCorrect bool
+		wantMatched string
+	}{
+		{"ё и е", 1, "Ёлка", []string{"елка"}, true, "елка"},
+		{"регистр и знаки препинания", 1, "Кот, в сапогах!", []string{"кот в сапогах"}, true, "кот в сапогах"},
+		{"дефис", 1, "Санкт-Петербург", []string{"санкт петербург"}, true, "санкт петербург"},
+		{"ровно на пороге", 0.8, "кошки", []string{"кошка"}, true, "кошка"},
+		{"чуть ниже порога", 0.81, "кошки", []string{"кошка"}, false, "кошка"},
+		{"порог 1 — только точное совпадение", 1, "масква", []string{"Москва"}, false, "Москва"},
+		{"опечатка прощается", 0.8, "масква", []string{"Москва"}, true, "Москва"},
+		{"число должно совпасть", 0.5, "1985", []string{"1984"}, false, "1984"},
+		{"лучший из синонимов", 0.8, "питер", []string{"Санкт-Петербург", "Питер"}, true, "Питер"},
+		{"нет вариантов", 0.8, "кот", nil, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAnswerMatcher(tt.threshold).Match(tt.guess, tt.accepted)
+			if got.Correct != tt.wantCorrect || got.Matched != tt.wantMatched {
+				t.Errorf("Match(%q, %q) = %+v, want correct=%v matched=%q", tt.guess, tt.accepted, got, tt.wantCorrect, tt.wantMatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// DraftService хранит незавершённый ввод пользователей бота, чтобы он пережил
+// перезапуск. Черновик, который не менялся дольше ttl, считается брошенным.
+type DraftService struct {
+	repo domain.DraftRepository
+	ttl  time.Duration
+}
+
+func NewDraftService(repo domain.DraftRepository, ttl time.Duration) *DraftService {
+	return &DraftService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Expired сообщает, брошен ли черновик, изменённый в updatedAt
+func (s *DraftService) Expired(updatedAt, now time.Time) bool {
+	return now.Sub(updatedAt) > s.ttl
+}
+
+// Save сохраняет состояние v как черновик вида kind
+func (s *DraftService) Save(ctx context.Context, userID int64, kind string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode draft: %w", err)
+	}
+	return s.repo.Save(ctx, &domain.Draft{UserID: userID, Kind: kind, Data: data})
+}
+
+// Load читает черновик в v и возвращает время его последнего изменения.
+// Если черновика нет или он брошен — domain.ErrNotFound.
+func (s *DraftService) Load(ctx context.Context, userID int64, kind string, v any) (time.Time, error) {
+	draft, err := s.repo.Get(ctx, userID, kind)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s.Expired(draft.UpdatedAt, time.Now()) {
+		s.Delete(ctx, userID, kind)
+		return time.Time{}, domain.ErrNotFound
+	}
+
+	if err := json.Unmarshal(draft.Data, v); err != nil {
+		return time.Time{}, fmt.Errorf("decode draft: %w", err)
+	}
+	return draft.UpdatedAt, nil
+}
+
+// Delete удаляет черновик; отсутствие черновика не ошибка
+func (s *DraftService) Delete(ctx context.Context, userID int64, kind string) {
+	if err := s.repo.Delete(ctx, userID, kind); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		log.Printf("Error deleting %s draft of %d: %v", kind, userID, err)
+	}
+}
+
+// List возвращает действующие черновики вида kind
+func (s *DraftService) List(ctx context.Context, kind string) ([]domain.Draft, error) {
+	drafts, err := s.repo.List(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var active []domain.Draft
+	for _, d := range drafts {
+		if !s.Expired(d.UpdatedAt, now) {
+			active = append(active, d)
+		}
+	}
+	return active, nil
+}
+
+// Cleanup удаляет брошенные черновики и возвращает их число
+func (s *DraftService) Cleanup(ctx context.Context) (int, error) {
+	return s.repo.DeleteOlder(ctx, time.Now().Add(-s.ttl))
+}
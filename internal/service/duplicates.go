@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/imagehash"
+)
+
+// maxHashDistance — сколько бит перцептивного хеша могут различаться у одного и того же снимка
+const maxHashDistance = 6
+
+// DuplicateReason — почему ситуация считается дублем
+type DuplicateReason string
+
+const (
+	DuplicateAnswer DuplicateReason = "answer" // тот же ответ без учёта регистра и знаков препинания
+	DuplicateFile   DuplicateReason = "file"   // тот же файл в Telegram
+	DuplicateImage  DuplicateReason = "image"  // похожее изображение
+)
+
+// Duplicate — уже добавленная ситуация, похожая на проверяемую
+type Duplicate struct {
+	SituationID int
+	Reason      DuplicateReason
+	Photo       int // номер совпавшего фото проверяемой ситуации, с 1; 0 — если совпал ответ
+}
+
+// PhotoFingerprint — отпечаток проверяемого фото; пустые поля не сравниваются
+type PhotoFingerprint struct {
+	FileUniqueID string
+	Hash         uint64
+}
+
+// FingerprintData считает отпечаток фото по содержимому файла
+func FingerprintData(data []byte) PhotoFingerprint {
+	hash, _ := imagehash.DHash(data)
+	return PhotoFingerprint{Hash: hash}
+}
+
+// DuplicateService ищет в библиотеке ситуации с тем же ответом или теми же фото
+type DuplicateService struct {
+	repo domain.SituationRepository
+}
+
+func NewDuplicateService(repo domain.SituationRepository) *DuplicateService {
+	return &DuplicateService{repo: repo}
+}
+
+// Find возвращает ситуации, похожие на новую: по ответу (answer может быть пустым)
+// и по каждому из фото. Одна ситуация попадает в список по каждой причине не больше раза.
+func (s *DuplicateService) Find(ctx context.Context, answer string, photos []PhotoFingerprint) ([]Duplicate, error) {
+	var duplicates []Duplicate
+
+	if normalized := domain.NormalizeAnswer(answer); normalized != "" {
+		ids, err := s.repo.FindByAnswer(ctx, normalized)
+		if err != nil {
+			return nil, fmt.Errorf("find by answer: %w", err)
+		}
+		for _, id := range ids {
+			duplicates = append(duplicates, Duplicate{SituationID: id, Reason: DuplicateAnswer})
+		}
+	}
+
+	if len(photos) == 0 {
+		return duplicates, nil
+	}
+
+	known, err := s.repo.ListPhotoFingerprints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list photo fingerprints: %w", err)
+	}
+
+	type seenKey struct {
+		situationID int
+		reason      DuplicateReason
+	}
+	seen := make(map[seenKey]bool)
+
+	for i, fp := range photos {
+		for _, p := range known {
+			var reason DuplicateReason
+			switch {
+			case fp.FileUniqueID != "" && fp.FileUniqueID == p.FileUniqueID:
+				reason = DuplicateFile
+			case fp.Hash != 0 && p.Hash != 0 && imagehash.Distance(fp.Hash, p.Hash) <= maxHashDistance:
+				reason = DuplicateImage
+			default:
+				continue
+			}
+
+			key := seenKey{p.SituationID, reason}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			duplicates = append(duplicates, Duplicate{SituationID: p.SituationID, Reason: reason, Photo: i + 1})
+		}
+	}
+
+	return duplicates, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/archive"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// exportPageSize — сколько ситуаций читается из базы за один запрос при экспорте
+const exportPageSize = 100
+
+// ExportResult — итог экспорта библиотеки
+type ExportResult struct {
+	Situations int
+	Photos     int
+	Failed     []error // фото, которые не удалось получить; в архив они не попали
+}
+
+// ExportService выгружает всю библиотеку ситуаций в ZIP-архив, который восстанавливается импортом
+type ExportService struct {
+	repo   domain.SituationRepository
+	decks  domain.DeckRepository
+	photos *PhotoService
+}
+
+func NewExportService(repo domain.SituationRepository, decks domain.DeckRepository, photos *PhotoService) *ExportService {
+	return &ExportService{
+		repo:   repo,
+		decks:  decks,
+		photos: photos,
+	}
+}
+
+// Export пишет в w архив с manifest.json и файлами всех фото. Фото, которых ещё нет
+// в хранилище, скачиваются из Telegram.
+func (s *ExportService) Export(ctx context.Context, w io.Writer) (*ExportResult, error) {
+	decks, err := s.decks.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list decks: %w", err)
+	}
+
+	manifest := archive.Manifest{
+		Version:    archive.Version,
+		ExportedAt: time.Now().UTC(),
+	}
+	deckNames := make(map[int]string, len(decks))
+	for _, d := range decks {
+		manifest.Decks = append(manifest.Decks, archive.Deck{Name: d.Name, Description: d.Description})
+		deckNames[d.ID] = d.Name
+	}
+
+	zw := archive.NewWriter(w)
+	result := &ExportResult{}
+
+	for offset := 0; ; offset += exportPageSize {
+		page, _, err := s.repo.List(ctx, offset, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("list situations: %w", err)
+		}
+
+		for _, summary := range page {
+			item, err := s.exportSituation(ctx, zw, summary.ID, deckNames, result)
+			if err != nil {
+				return nil, err
+			}
+			manifest.Situations = append(manifest.Situations, *item)
+			result.Situations++
+		}
+
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	if err := zw.Close(manifest); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ExportService) exportSituation(ctx context.Context, zw *archive.Writer, id int, deckNames map[int]string, result *ExportResult) (*archive.Item, error) {
+	situation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get situation %d: %w", id, err)
+	}
+
+	deckIDs, err := s.repo.GetDeckIDs(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get decks of situation %d: %w", id, err)
+	}
+
+	item := &archive.Item{
+		ID:         situation.Situation.ID,
+		Answer:     situation.Situation.Answer,
+		Aliases:    situation.Situation.Aliases,
+		Hints:      situation.Situation.Hints,
+		Difficulty: situation.Situation.Difficulty,
+		Used:       situation.Situation.IsUsed,
+		CreatedAt:  situation.Situation.CreatedAt.UTC(),
+	}
+	for _, deckID := range deckIDs {
+		if name, ok := deckNames[deckID]; ok {
+			item.Decks = append(item.Decks, name)
+		}
+	}
+	slices.Sort(item.Decks)
+
+	for _, photo := range situation.Photos {
+		data, ext, err := s.readPhoto(ctx, photo.ID)
+		if err != nil {
+			result.Failed = append(result.Failed, fmt.Errorf("situation %d, photo %d: %w", id, photo.ID, err))
+			continue
+		}
+
+		name := fmt.Sprintf("situations/%d/%d%s", id, photo.SortOrder, ext)
+		if err := zw.AddFile(name, data); err != nil {
+			return nil, err
+		}
+		item.Photos = append(item.Photos, name)
+		item.SortOrders = append(item.SortOrders, photo.SortOrder)
+		result.Photos++
+	}
+
+	return item, nil
+}
+
+// readPhoto возвращает содержимое фото и расширение файла
+func (s *ExportService) readPhoto(ctx context.Context, photoID int) ([]byte, string, error) {
+	r, photo, err := s.photos.Open(ctx, photoID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ext := path.Ext(photo.StorageKey)
+	if ext == "" {
+		ext = ".jpg"
+	}
+	return data, ext, nil
+}
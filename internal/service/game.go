@@ -3,120 +3,456 @@ package service
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/plastinin/photo-quiz-bot/internal/domain"
-	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
 )
 
 var (
 	ErrNoSituations   = errors.New("нет доступных задач")
 	ErrNoMorePhotos   = errors.New("больше нет фотографий")
 	ErrGameNotStarted = errors.New("игра не начата, используйте /start")
+	ErrAnswerShown    = errors.New("ответ уже открыт")
 )
 
+// GameService — единый игровой движок для бота и веб-интерфейса.
+// Ведёт раунды (у каждого чата Telegram и каждой веб-комнаты своё состояние),
+// очерёдность ходов и начисление BazuCoin в веб-комнатах.
 type GameService struct {
-	repo  *postgres.SituationRepository
-	state *GameState
-	mu    sync.RWMutex
+	repo     domain.SituationRepository
+	matcher  *AnswerMatcher
+	states   map[string]*GameState
+	filters  map[string]domain.SituationFilter // выбранные колоды и сложности игр в чатах Telegram
+	balance  map[string]int                    // с какой сложности начинать следующий раунд в режиме чередования
+	timers   map[string]time.Duration          // таймеры на фото, выбранные в чатах Telegram
+	roundSeq int
+	mu       sync.RWMutex
+
+	defaultTimer time.Duration // таймер на фото для чатов, где он не выбран
+
+	sessionRepo  domain.SessionRepository
+	sessions     map[string]*domain.GameSession // ключ — код комнаты
+	chatSessions map[int64]string               // чаты Telegram, привязанные к комнатам
+	sessionsMu   sync.RWMutex
+
+	TurnEndChan chan TurnEndEvent
+	TimerChan   chan TimerEvent
 }
 
 type GameState struct {
+	RoundID          int
 	CurrentSituation *domain.SituationWithPhotos
 	CurrentPhotoIdx  int
+	AnswerShown      bool
+	WrongGuesses     int       // неверные догадки в режиме угадывания
+	HintsShown       int       // сколько текстовых подсказок открыто
+	Deadline         time.Time // когда таймер откроет следующее фото или ответ; пусто — таймера нет
+
+	timer *time.Timer
+}
+
+// RoundSnapshot — открытая часть текущего раунда для отображения на другом экране
+type RoundSnapshot struct {
+	RoundID     int
+	Photos      []domain.Photo
+	TotalPhotos int
+	Difficulty  int
+	Answer      string
+	AnswerShown bool
+	Deadline    time.Time
+	Hints       []string // открытые подсказки
+	TotalHints  int
+}
+
+// DifficultyMultiplier — во сколько раз умножаются BazuCoin за ситуацию данной сложности
+func DifficultyMultiplier(difficulty int) float64 {
+	switch difficulty {
+	case domain.DifficultyEasy:
+		return 1
+	case domain.DifficultyHard:
+		return 2
+	default:
+		return 1.5
+	}
 }
 
-func NewGameService(repo *postgres.SituationRepository) *GameService {
+func NewGameService(repo domain.SituationRepository, sessionRepo domain.SessionRepository, matcher *AnswerMatcher) *GameService {
 	return &GameService{
-		repo:  repo,
-		state: &GameState{},
+		repo:         repo,
+		matcher:      matcher,
+		states:       make(map[string]*GameState),
+		filters:      make(map[string]domain.SituationFilter),
+		balance:      make(map[string]int),
+		timers:       make(map[string]time.Duration),
+		sessionRepo:  sessionRepo,
+		sessions:     make(map[string]*domain.GameSession),
+		chatSessions: make(map[int64]string),
+		TurnEndChan:  make(chan TurnEndEvent, 10),
+		TimerChan:    make(chan TimerEvent, 10),
 	}
 }
 
-func (s *GameService) StartNewRound(ctx context.Context) (*domain.Photo, error) {
+// ChatKey — ключ состояния игры для чата Telegram
+func ChatKey(chatID int64) string {
+	return "chat:" + strconv.FormatInt(chatID, 10)
+}
+
+// SessionKey — ключ состояния игры для веб-комнаты
+func SessionKey(code string) string {
+	return "session:" + code
+}
+
+func sessionCodeFromKey(key string) (string, bool) {
+	return strings.CutPrefix(key, "session:")
+}
+
+// SetFilter задаёт колоды и сложности, из которых игра key берёт ситуации; веб-комнаты выбирают их при создании
+func (s *GameService) SetFilter(key string, filter domain.SituationFilter) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	situation, err := s.repo.GetRandomUnused(ctx)
-	if err != nil {
-		if errors.Is(err, postgres.ErrNotFound) {
-			return nil, ErrNoSituations
+	if filter.IsEmpty() {
+		delete(s.filters, key)
+		return
+	}
+	s.filters[key] = filter
+}
+
+// Filter возвращает фильтр ситуаций игры key
+func (s *GameService) Filter(key string) domain.SituationFilter {
+	if code, ok := sessionCodeFromKey(key); ok {
+		s.sessionsMu.RLock()
+		defer s.sessionsMu.RUnlock()
+
+		if session := s.sessions[code]; session != nil {
+			return session.Filter()
 		}
+		return domain.SituationFilter{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.filters[key]
+}
+
+func (s *GameService) StartNewRound(ctx context.Context, key string) (*domain.Photo, error) {
+	filter := s.Filter(key)
+	timer := s.Timer(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	situation, err := s.pickSituation(ctx, key, filter)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(situation.Photos) == 0 {
-		// Если у ситуации нет фото, помечаем её использованной и пробуем снова
-		_ = s.repo.MarkAsUsed(ctx, situation.Situation.ID)
-		s.mu.Unlock()
-		return s.StartNewRound(ctx)
+	if old := s.states[key]; old != nil {
+		old.stopTimer()
 	}
 
-	s.state.CurrentSituation = situation
-	s.state.CurrentPhotoIdx = 0
+	s.roundSeq++
+	state := &GameState{
+		RoundID:          s.roundSeq,
+		CurrentSituation: situation,
+		CurrentPhotoIdx:  0,
+	}
+	s.states[key] = state
+	s.armTimer(key, state, timer)
 
 	return &situation.Photos[0], nil
 }
 
-func (s *GameService) NextPhoto(ctx context.Context) (*domain.Photo, error) {
+// pickSituation выбирает ситуацию для нового раунда игры key; вызывать под блокировкой
+func (s *GameService) pickSituation(ctx context.Context, key string, filter domain.SituationFilter) (*domain.SituationWithPhotos, error) {
+	if !filter.Balanced {
+		return s.randomSituation(ctx, filter)
+	}
+
+	// Чередуем сложности: каждый раунд начинаем со следующего уровня, пропуская закончившиеся
+	levels := filter.Difficulties
+	if len(levels) == 0 {
+		levels = domain.Difficulties
+	}
+
+	start := s.balance[key]
+	for i := range levels {
+		level := (start + i) % len(levels)
+
+		levelFilter := filter
+		levelFilter.Difficulties = []int{levels[level]}
+
+		situation, err := s.randomSituation(ctx, levelFilter)
+		if errors.Is(err, ErrNoSituations) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.balance[key] = (level + 1) % len(levels)
+		return situation, nil
+	}
+
+	return nil, ErrNoSituations
+}
+
+// randomSituation выбирает случайную несыгранную ситуацию с фотографиями
+func (s *GameService) randomSituation(ctx context.Context, filter domain.SituationFilter) (*domain.SituationWithPhotos, error) {
+	for {
+		situation, err := s.repo.GetRandomUnused(ctx, filter)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, ErrNoSituations
+			}
+			return nil, err
+		}
+
+		if len(situation.Photos) == 0 {
+			// Если у ситуации нет фото, помечаем её использованной и пробуем снова
+			if err := s.repo.MarkAsUsed(ctx, situation.Situation.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return situation, nil
+	}
+}
+
+// NextPhoto открывает следующее фото; таймер раунда при этом отсчитывается заново
+func (s *GameService) NextPhoto(ctx context.Context, key string) (*domain.Photo, error) {
+	timer := s.Timer(key)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.state.CurrentSituation == nil {
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
 		return nil, ErrGameNotStarted
 	}
 
-	nextIdx := s.state.CurrentPhotoIdx + 1
-	if nextIdx >= len(s.state.CurrentSituation.Photos) {
+	nextIdx := state.CurrentPhotoIdx + 1
+	if nextIdx >= len(state.CurrentSituation.Photos) {
 		return nil, ErrNoMorePhotos
 	}
 
-	s.state.CurrentPhotoIdx = nextIdx
-	return &s.state.CurrentSituation.Photos[nextIdx], nil
+	state.CurrentPhotoIdx = nextIdx
+	if !state.AnswerShown {
+		s.armTimer(key, state, timer)
+	}
+	return &state.CurrentSituation.Photos[nextIdx], nil
 }
 
-func (s *GameService) GetAnswer(ctx context.Context) (string, error) {
+// GetCurrentPhoto возвращает последнее открытое фото текущего раунда
+func (s *GameService) GetCurrentPhoto(key string) (*domain.Photo, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if s.state.CurrentSituation == nil {
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return nil, ErrGameNotStarted
+	}
+
+	return &state.CurrentSituation.Photos[state.CurrentPhotoIdx], nil
+}
+
+func (s *GameService) GetAnswer(ctx context.Context, key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return "", ErrGameNotStarted
+	}
+
+	return state.CurrentSituation.Situation.Answer, nil
+}
+
+// CheckGuess сравнивает догадку с ответом и синонимами ситуации текущего раунда.
+// Ответ при этом не открывается.
+func (s *GameService) CheckGuess(key, guess string) (AnswerMatch, error) {
+	s.mu.RLock()
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		s.mu.RUnlock()
+		return AnswerMatch{}, ErrGameNotStarted
+	}
+	accepted := state.CurrentSituation.Situation.AcceptedAnswers()
+	s.mu.RUnlock()
+
+	return s.matcher.Match(guess, accepted), nil
+}
+
+// RoundGuess — результат догадки, проверенной в текущем раунде
+type RoundGuess struct {
+	AnswerMatch
+	Answer       string // ответ ситуации; заполнен, только если догадка верна
+	Difficulty   int
+	PhotosShown  int
+	HintsShown   int
+	WrongGuesses int // неверные догадки раунда, включая эту
+}
+
+// Guess проверяет догадку в текущем раунде. Первая верная догадка открывает ответ,
+// поэтому следующие — даже верные — получают ErrAnswerShown; неверные считаются.
+func (s *GameService) Guess(key, guess string) (*RoundGuess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return nil, ErrGameNotStarted
+	}
+	if state.AnswerShown {
+		return nil, ErrAnswerShown
+	}
+
+	situation := state.CurrentSituation.Situation
+	result := &RoundGuess{
+		AnswerMatch: s.matcher.Match(guess, situation.AcceptedAnswers()),
+		Difficulty:  situation.Difficulty,
+		PhotosShown: state.CurrentPhotoIdx + 1,
+		HintsShown:  state.HintsShown,
+	}
+	if result.Correct {
+		state.stopTimer()
+		state.AnswerShown = true
+		result.Answer = situation.Answer
+	} else {
+		state.WrongGuesses++
+	}
+	result.WrongGuesses = state.WrongGuesses
+
+	return result, nil
+}
+
+// ShowAnswer открывает ответ; в веб-комнате это завершает ход и запрашивает очки у ведущего
+func (s *GameService) ShowAnswer(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		s.mu.Unlock()
 		return "", ErrGameNotStarted
 	}
+	state.stopTimer()
+	state.AnswerShown = true
+	answer := state.CurrentSituation.Situation.Answer
+	s.mu.Unlock()
+
+	if code, ok := sessionCodeFromKey(key); ok {
+		s.NotifyTurnEnd(code)
+	}
 
-	return s.state.CurrentSituation.Situation.Answer, nil
+	return answer, nil
 }
 
-func (s *GameService) FinishRound(ctx context.Context) error {
+// NextTurn завершает текущий раунд и начинает новый; в веб-комнате ход переходит к следующему игроку.
+// Ход передаётся только после того, как новый раунд начался: иначе повтор пропустил бы игрока.
+func (s *GameService) NextTurn(ctx context.Context, key string) (*domain.Photo, error) {
+	code, isSession := sessionCodeFromKey(key)
+	if isSession {
+		if _, err := s.GetSession(code); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.FinishRound(ctx, key); err != nil && !errors.Is(err, ErrGameNotStarted) {
+		return nil, err
+	}
+
+	photo, err := s.StartNewRound(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if isSession {
+		if _, err := s.NextPlayer(ctx, code); err != nil && !errors.Is(err, ErrNoActiveSession) {
+			return nil, err
+		}
+	}
+	return photo, nil
+}
+
+// Snapshot возвращает открытые фото и, если он уже показан, ответ текущего раунда
+func (s *GameService) Snapshot(key string) (*RoundSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return nil, ErrGameNotStarted
+	}
+
+	snapshot := &RoundSnapshot{
+		RoundID:     state.RoundID,
+		Photos:      append([]domain.Photo(nil), state.CurrentSituation.Photos[:state.CurrentPhotoIdx+1]...),
+		TotalPhotos: len(state.CurrentSituation.Photos),
+		Difficulty:  state.CurrentSituation.Situation.Difficulty,
+		AnswerShown: state.AnswerShown,
+		Deadline:    state.Deadline,
+		Hints:       append([]string(nil), state.CurrentSituation.Situation.Hints[:state.HintsShown]...),
+		TotalHints:  len(state.CurrentSituation.Situation.Hints),
+	}
+	if state.AnswerShown {
+		snapshot.Answer = state.CurrentSituation.Situation.Answer
+	}
+
+	return snapshot, nil
+}
+
+func (s *GameService) FinishRound(ctx context.Context, key string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.state.CurrentSituation == nil {
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
 		return ErrGameNotStarted
 	}
 
-	err := s.repo.MarkAsUsed(ctx, s.state.CurrentSituation.Situation.ID)
+	err := s.repo.MarkAsUsed(ctx, state.CurrentSituation.Situation.ID)
 	if err != nil {
 		return err
 	}
 
-	s.state.CurrentSituation = nil
-	s.state.CurrentPhotoIdx = 0
+	state.stopTimer()
+	delete(s.states, key)
 
 	return nil
 }
 
+// EndGame забывает состояние игры без отметки ситуации сыгранной
+func (s *GameService) EndGame(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state := s.states[key]; state != nil {
+		state.stopTimer()
+	}
+	delete(s.states, key)
+	delete(s.balance, key)
+}
+
+// ResetGame делает все ситуации снова доступными и прерывает все текущие раунды
 func (s *GameService) ResetGame(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.state.CurrentSituation = nil
-	s.state.CurrentPhotoIdx = 0
+	for _, state := range s.states {
+		state.stopTimer()
+	}
+	s.states = make(map[string]*GameState)
+	s.balance = make(map[string]int)
 
 	return s.repo.ResetAllUsed(ctx)
 }
 
-func (s *GameService) GetStats(ctx context.Context) (total, used, remaining int, err error) {
-	total, used, err = s.repo.GetStats(ctx)
+func (s *GameService) GetStats(ctx context.Context, filter domain.SituationFilter) (total, used, remaining int, err error) {
+	total, used, err = s.repo.GetStats(ctx, filter)
 	if err != nil {
 		return 0, 0, 0, err
 	}
@@ -124,13 +460,39 @@ func (s *GameService) GetStats(ctx context.Context) (total, used, remaining int,
 	return total, used, remaining, nil
 }
 
-func (s *GameService) GetCurrentPhotoInfo() (current, total int, err error) {
+// IsSituationInPlay сообщает, идёт ли сейчас раунд с этой ситуацией хотя бы в одной игре
+func (s *GameService) IsSituationInPlay(situationID int) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if s.state.CurrentSituation == nil {
+	for _, state := range s.states {
+		if state.CurrentSituation != nil && state.CurrentSituation.Situation.ID == situationID {
+			return true
+		}
+	}
+	return false
+}
+
+// roundDifficulty возвращает сложность ситуации текущего раунда игры key
+func (s *GameService) roundDifficulty(key string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return 0, false
+	}
+	return state.CurrentSituation.Situation.Difficulty, true
+}
+
+func (s *GameService) GetCurrentPhotoInfo(key string) (current, total int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
 		return 0, 0, ErrGameNotStarted
 	}
 
-	return s.state.CurrentPhotoIdx + 1, len(s.state.CurrentSituation.Photos), nil
-}
\ No newline at end of file
+	return state.CurrentPhotoIdx + 1, len(state.CurrentSituation.Photos), nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// ErrGuessingOff — режим угадывания в чате выключен или чат управляет веб-комнатой
+var ErrGuessingOff = errors.New("режим угадывания выключен")
+
+// GuessResult — итог догадки игрока в режиме угадывания
+type GuessResult struct {
+	*RoundGuess
+	Points float64 // начисленные BazuCoin; 0 для неверной догадки
+}
+
+// GuessService ведёт режим угадывания в чатах Telegram: ответы игроков текстом
+// сверяются с ответом текущего раунда, угадавший первым получает BazuCoin по DefaultScoreRule:
+// чем меньше фото понадобилось, тем больше.
+// В групповых чатах режим включён, пока его не выключат; в личных — наоборот.
+type GuessService struct {
+	game   *GameService
+	scores domain.ChatScoreRepository
+
+	modes map[int64]bool // чаты, где режим переключили вручную
+	mu    sync.RWMutex
+}
+
+func NewGuessService(game *GameService, scores domain.ChatScoreRepository) *GuessService {
+	return &GuessService{
+		game:   game,
+		scores: scores,
+		modes:  make(map[int64]bool),
+	}
+}
+
+// Enabled сообщает, проверяются ли сообщения чата как догадки
+func (s *GuessService) Enabled(chatID int64, group bool) bool {
+	if _, attached := s.game.AttachedSession(chatID); attached {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if on, ok := s.modes[chatID]; ok {
+		return on
+	}
+	return group
+}
+
+// SetEnabled включает или выключает режим угадывания в чате
+func (s *GuessService) SetEnabled(chatID int64, on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.modes[chatID] = on
+}
+
+// Check проверяет догадку игрока в игре чата и записывает её в итоги игрока.
+// ErrGameNotStarted и ErrAnswerShown означают, что угадывать сейчас нечего.
+func (s *GuessService) Check(ctx context.Context, chatID, userID int64, name, guess string) (*GuessResult, error) {
+	if _, attached := s.game.AttachedSession(chatID); attached {
+		return nil, ErrGuessingOff
+	}
+
+	round, err := s.game.Guess(ChatKey(chatID), guess)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GuessResult{RoundGuess: round}
+	correct, wrong := 0, 1
+	if round.Correct {
+		base, _ := RuleScore(DefaultScoreRule, round.PhotosShown)
+		result.Points = HintedScore(base, round.HintsShown) * DifficultyMultiplier(round.Difficulty)
+		correct, wrong = 1, 0
+	}
+
+	// Раунд уже засчитан: ошибка записи итогов не должна мешать игре
+	if err := s.scores.Add(ctx, chatID, userID, name, result.Points, correct, wrong); err != nil {
+		return result, fmt.Errorf("save guess: %w", err)
+	}
+	return result, nil
+}
+
+// Scoreboard возвращает итоги игроков чата
+func (s *GuessService) Scoreboard(ctx context.Context, chatID int64) ([]domain.ChatPlayerScore, error) {
+	return s.scores.List(ctx, chatID)
+}
+
+// ResetScores обнуляет итоги игроков чата
+func (s *GuessService) ResetScores(ctx context.Context, chatID int64) error {
+	return s.scores.Reset(ctx, chatID)
+}
@@ -0,0 +1,57 @@
+package service
+
+import "errors"
+
+// HintPenalty — на сколько BazuCoin уменьшаются очки за ход каждая открытая подсказка (до умножения на сложность)
+const HintPenalty = 0.5
+
+var ErrNoMoreHints = errors.New("больше нет подсказок")
+
+// HintResult — открытая подсказка текущего раунда
+type HintResult struct {
+	Text  string
+	Shown int // сколько подсказок открыто, включая эту
+	Total int
+}
+
+// HintedScore возвращает BazuCoin за ход после вычета за shown открытых подсказок; меньше 0 не бывает
+func HintedScore(base float64, shown int) float64 {
+	return max(base-float64(shown)*HintPenalty, 0)
+}
+
+// ShowHint открывает следующую подсказку раунда; каждая открытая подсказка уменьшает очки за ход
+func (s *GameService) ShowHint(key string) (*HintResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return nil, ErrGameNotStarted
+	}
+	if state.AnswerShown {
+		return nil, ErrAnswerShown
+	}
+
+	hints := state.CurrentSituation.Situation.Hints
+	if state.HintsShown >= len(hints) {
+		return nil, ErrNoMoreHints
+	}
+
+	state.HintsShown++
+	return &HintResult{
+		Text:  hints[state.HintsShown-1],
+		Shown: state.HintsShown,
+		Total: len(hints),
+	}, nil
+}
+
+// roundHints возвращает, сколько подсказок открыто в текущем раунде игры key
+func (s *GameService) roundHints(key string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if state := s.states[key]; state != nil {
+		return state.HintsShown
+	}
+	return 0
+}
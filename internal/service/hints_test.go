@@ -0,0 +1,25 @@
+package service
+
+import "testing"
+
+func TestHintedScore(t *testing.T) {
+	tests := []struct {
+		base  float64
+		shown int
+		want  float64
+	}{
+		{3, 0, 3},
+		{3, 1, 2.5},
+		{3, 2, 2},
+		{1, 2, 0},
+		{1, 3, 0},
+		{0, 1, 0},
+		{0.5, 1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := HintedScore(tt.base, tt.shown); got != tt.want {
+			t.Errorf("HintedScore(%v, %d) = %v, want %v", tt.base, tt.shown, got, tt.want)
+		}
+	}
+}
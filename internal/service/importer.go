@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/plastinin/photo-quiz-bot/internal/archive"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// PhotoUploader загружает фото в Telegram и возвращает его file_id
+type PhotoUploader interface {
+	Upload(ctx context.Context, name string, data []byte) (fileID string, err error)
+}
+
+// ImportItemResult — итог импорта одной ситуации архива
+type ImportItemResult struct {
+	Line        int // номер ситуации в манифесте, с 1
+	Answer      string
+	SituationID int // 0, если ситуация не создана
+	Photos      int
+	Err         error
+
+	// Уже добавленные ситуации, на которые похожа эта: ситуация всё равно создаётся
+	Duplicates []Duplicate
+}
+
+// ImportService создаёт ситуации из ZIP-архива
+type ImportService struct {
+	repo       domain.SituationRepository
+	decks      domain.DeckRepository
+	photos     *PhotoService
+	duplicates *DuplicateService
+}
+
+func NewImportService(repo domain.SituationRepository, decks domain.DeckRepository, photos *PhotoService, duplicates *DuplicateService) *ImportService {
+	return &ImportService{
+		repo:       repo,
+		decks:      decks,
+		photos:     photos,
+		duplicates: duplicates,
+	}
+}
+
+// importItem — ситуация архива, прошедшая проверку
+type importItem struct {
+	result    *ImportItemResult
+	situation domain.NewSituation
+	files     []PhotoFile
+}
+
+// Import проверяет ситуации архива, загружает их фото в Telegram через uploader и создаёт все
+// корректные ситуации одной транзакцией. Ошибки отдельных ситуаций попадают в отчёт,
+// ошибка возвращается, если архив пуст или транзакция не удалась.
+func (s *ImportService) Import(ctx context.Context, a *archive.Archive, uploader PhotoUploader) ([]ImportItemResult, error) {
+	if len(a.Manifest.Situations) == 0 {
+		return nil, errors.New("в манифесте нет ситуаций")
+	}
+
+	if err := s.createDecks(ctx, a.Manifest.Decks); err != nil {
+		return nil, err
+	}
+
+	decks, err := s.deckIDsByName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ImportItemResult, len(a.Manifest.Situations))
+	var items []importItem
+	for i, entry := range a.Manifest.Situations {
+		results[i] = ImportItemResult{Line: i + 1, Answer: strings.TrimSpace(entry.Answer)}
+
+		item, err := s.prepare(a, entry, decks)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		item.result = &results[i]
+		items = append(items, *item)
+
+		// Дубли только помечаем в отчёте: решать, удалять ли, администратору
+		fingerprints := make([]PhotoFingerprint, len(item.files))
+		for j, f := range item.files {
+			fingerprints[j] = FingerprintData(f.Data)
+		}
+		if results[i].Duplicates, err = s.duplicates.Find(ctx, item.situation.Answer, fingerprints); err != nil {
+			log.Printf("Error finding duplicates of %q: %v", item.situation.Answer, err)
+		}
+	}
+
+	// Загружаем фото в Telegram: по file_id их показывает бот
+	var ready []importItem
+	for _, item := range items {
+		if err := UploadPhotos(ctx, uploader, item.files); err != nil {
+			item.result.Err = err
+			continue
+		}
+		for _, f := range item.files {
+			item.situation.PhotoFileIDs = append(item.situation.PhotoFileIDs, f.FileID)
+		}
+		ready = append(ready, item)
+	}
+
+	if len(ready) == 0 {
+		return results, nil
+	}
+
+	situations := make([]domain.NewSituation, len(ready))
+	for i, item := range ready {
+		situations[i] = item.situation
+	}
+
+	ids, err := s.repo.CreateBatch(ctx, situations)
+	if err != nil {
+		return nil, fmt.Errorf("create situations: %w", err)
+	}
+
+	for i, item := range ready {
+		item.result.SituationID = ids[i]
+		item.result.Photos = len(item.files)
+
+		// Файлы уже в памяти, поэтому кладём их в хранилище сразу, не скачивая из Telegram
+		if err := s.photos.PutSituationPhotos(ctx, ids[i], item.files); err != nil {
+			log.Printf("Error storing photos of situation %d: %v", ids[i], err)
+		}
+	}
+
+	return results, nil
+}
+
+// prepare проверяет ситуацию манифеста и читает её фото из архива
+func (s *ImportService) prepare(a *archive.Archive, entry archive.Item, decks map[string]int) (*importItem, error) {
+	if entry.Err != nil {
+		return nil, entry.Err
+	}
+
+	answer := strings.TrimSpace(entry.Answer)
+	if answer == "" {
+		return nil, errors.New("не указан ответ")
+	}
+
+	aliases := domain.CleanAliases(answer, entry.Aliases)
+	if len(aliases) > domain.MaxAliases {
+		return nil, fmt.Errorf("синонимов %d, а можно не больше %d", len(aliases), domain.MaxAliases)
+	}
+
+	hints := domain.CleanHints(entry.Hints)
+	if len(hints) > domain.MaxHints {
+		return nil, fmt.Errorf("подсказок %d, а можно не больше %d", len(hints), domain.MaxHints)
+	}
+
+	difficulty := entry.Difficulty
+	if difficulty == 0 {
+		difficulty = domain.DifficultyMedium
+	}
+	if difficulty < domain.DifficultyEasy || difficulty > domain.DifficultyHard {
+		return nil, fmt.Errorf("сложность должна быть от %d до %d", domain.DifficultyEasy, domain.DifficultyHard)
+	}
+
+	var deckIDs []int
+	for _, name := range entry.Decks {
+		id, ok := decks[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("колода «%s» не найдена", name)
+		}
+		deckIDs = append(deckIDs, id)
+	}
+
+	names, err := a.Photos(entry)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > domain.MaxPhotosPerSituation {
+		return nil, fmt.Errorf("фото %d, а можно не больше %d", len(names), domain.MaxPhotosPerSituation)
+	}
+
+	item := &importItem{
+		situation: domain.NewSituation{
+			Answer:     answer,
+			Aliases:    aliases,
+			Hints:      hints,
+			Difficulty: difficulty,
+			IsUsed:     entry.Used,
+			DeckIDs:    deckIDs,
+		},
+	}
+	for _, name := range names {
+		data, err := a.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		f := PhotoFile{Name: name, Data: data}
+		if err := CheckPhotoFile(f); err != nil {
+			return nil, err
+		}
+		item.files = append(item.files, f)
+	}
+
+	return item, nil
+}
+
+// createDecks создаёт колоды из манифеста, которых ещё нет (например, при восстановлении копии)
+func (s *ImportService) createDecks(ctx context.Context, decks []archive.Deck) error {
+	for _, d := range decks {
+		name := strings.TrimSpace(d.Name)
+		if name == "" {
+			continue
+		}
+		if _, err := s.decks.Create(ctx, name, d.Description); err != nil && !errors.Is(err, domain.ErrAlreadyExists) {
+			return fmt.Errorf("create deck %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// deckIDsByName возвращает ID колод по названию в нижнем регистре
+func (s *ImportService) deckIDsByName(ctx context.Context) (map[string]int, error) {
+	decks, err := s.decks.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list decks: %w", err)
+	}
+
+	ids := make(map[string]int, len(decks))
+	for _, d := range decks {
+		ids[strings.ToLower(d.Name)] = d.ID
+	}
+	return ids, nil
+}
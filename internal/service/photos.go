@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/storage"
+)
+
+// PhotoFetcher скачивает оригинал фото из Telegram по file_id
+type PhotoFetcher interface {
+	// Fetch возвращает содержимое файла и его имя на серверах Telegram
+	Fetch(ctx context.Context, fileID string) (data []byte, filePath string, err error)
+}
+
+// PhotoFile — фото, загруженное администратором (из архива или веб-формы), а не присланное в Telegram
+type PhotoFile struct {
+	Name   string // имя файла, по нему выбирается расширение в хранилище
+	Data   []byte
+	FileID string // file_id в Telegram, заполняется UploadPhotos
+}
+
+// CheckPhotoFile проверяет, что файл — изображение, которое умеет показывать Telegram
+func CheckPhotoFile(f PhotoFile) error {
+	switch http.DetectContentType(f.Data) {
+	case "image/jpeg", "image/png", "image/webp":
+		return nil
+	}
+	return fmt.Errorf("%s — не изображение JPEG, PNG или WebP", f.Name)
+}
+
+// UploadPhotos загружает фото в Telegram и заполняет их FileID: бот показывает фото по file_id
+func UploadPhotos(ctx context.Context, uploader PhotoUploader, files []PhotoFile) error {
+	for i := range files {
+		fileID, err := uploader.Upload(ctx, files[i].Name, files[i].Data)
+		if err != nil {
+			return fmt.Errorf("не удалось загрузить %s: %w", files[i].Name, err)
+		}
+		files[i].FileID = fileID
+	}
+	return nil
+}
+
+// PhotoService скачивает фото из Telegram один раз и дальше отдаёт их из локального хранилища
+type PhotoService struct {
+	repo    domain.SituationRepository
+	store   storage.BlobStore
+	fetcher PhotoFetcher
+}
+
+func NewPhotoService(repo domain.SituationRepository, store storage.BlobStore, fetcher PhotoFetcher) *PhotoService {
+	return &PhotoService{
+		repo:    repo,
+		store:   store,
+		fetcher: fetcher,
+	}
+}
+
+// StoreSituationPhotos скачивает в хранилище все ещё не сохранённые фото ситуации
+func (s *PhotoService) StoreSituationPhotos(ctx context.Context, situationID int) error {
+	situation, err := s.repo.GetByID(ctx, situationID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range situation.Photos {
+		photo := &situation.Photos[i]
+		if photo.StorageKey != "" {
+			continue
+		}
+		if err := s.storePhoto(ctx, photo); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Open открывает фото для отдачи клиенту. Старые фото, ещё не лежащие в хранилище,
+// скачиваются из Telegram при первом обращении.
+func (s *PhotoService) Open(ctx context.Context, photoID int) (io.ReadSeekCloser, *domain.Photo, error) {
+	photo, err := s.repo.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if photo.StorageKey != "" {
+		r, err := s.store.Open(ctx, photo.StorageKey)
+		if err == nil {
+			return r, photo, nil
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, err
+		}
+		// Файл пропал из хранилища — скачиваем заново
+	}
+
+	if err := s.storePhoto(ctx, photo); err != nil {
+		return nil, nil, err
+	}
+
+	r, err := s.store.Open(ctx, photo.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, photo, nil
+}
+
+// DeletePhoto удаляет фото из базы и из хранилища
+func (s *PhotoService) DeletePhoto(ctx context.Context, photoID int) error {
+	photo, err := s.repo.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeletePhoto(ctx, photoID); err != nil {
+		return err
+	}
+
+	return s.deleteBlob(ctx, photo.StorageKey)
+}
+
+// DeleteSituation удаляет ситуацию вместе с файлами её фото
+func (s *PhotoService) DeleteSituation(ctx context.Context, situationID int) error {
+	situation, err := s.repo.GetByID(ctx, situationID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, situationID); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, photo := range situation.Photos {
+		errs = append(errs, s.deleteBlob(ctx, photo.StorageKey))
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteAll удаляет все ситуации и файлы их фото; возвращает число удалённых ситуаций
+func (s *PhotoService) DeleteAll(ctx context.Context) (int, error) {
+	keys, err := s.repo.ListStorageKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.repo.DeleteAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		errs = append(errs, s.deleteBlob(ctx, key))
+	}
+	return count, errors.Join(errs...)
+}
+
+// FingerprintResult — итог досчёта хешей фото
+type FingerprintResult struct {
+	Hashed  int
+	Skipped int     // файлы, по которым хеш не считается (WebP, повреждённые)
+	Failed  []error // фото, которые не удалось получить
+}
+
+// FingerprintStored досчитывает перцептивные хеши фото, добавленных до поиска дублей по фото.
+// Фото берутся из хранилища, а ещё не скачанные — из Telegram.
+func (s *PhotoService) FingerprintStored(ctx context.Context) (FingerprintResult, error) {
+	var result FingerprintResult
+
+	photos, err := s.repo.ListUnhashedPhotos(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	for _, photo := range photos {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		r, _, err := s.Open(ctx, photo.ID)
+		if err != nil {
+			result.Failed = append(result.Failed, fmt.Errorf("photo %d: %w", photo.ID, err))
+			continue
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			result.Failed = append(result.Failed, fmt.Errorf("read photo %d: %w", photo.ID, err))
+			continue
+		}
+
+		fp := FingerprintData(data)
+		if fp.Hash == 0 {
+			result.Skipped++
+			continue
+		}
+		if err := s.repo.SetPhotoFingerprint(ctx, photo.ID, "", fp.Hash); err != nil {
+			return result, err
+		}
+		result.Hashed++
+	}
+
+	return result, nil
+}
+
+func (s *PhotoService) deleteBlob(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+	if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("delete photo file %s: %w", key, err)
+	}
+	return nil
+}
+
+// storePhoto скачивает фото из Telegram, кладёт в хранилище и запоминает ключ в базе
+func (s *PhotoService) storePhoto(ctx context.Context, photo *domain.Photo) error {
+	if s.fetcher == nil {
+		return fmt.Errorf("photo %d is not stored and Telegram is unavailable", photo.ID)
+	}
+
+	data, filePath, err := s.fetcher.Fetch(ctx, photo.FileID)
+	if err != nil {
+		return fmt.Errorf("fetch photo %d: %w", photo.ID, err)
+	}
+
+	return s.PutPhoto(ctx, photo, data, filePath)
+}
+
+// FingerprintTelegramPhoto скачивает фото из Telegram и считает его отпечаток для поиска дублей
+func (s *PhotoService) FingerprintTelegramPhoto(ctx context.Context, fileID, fileUniqueID string) (PhotoFingerprint, error) {
+	fp := PhotoFingerprint{FileUniqueID: fileUniqueID}
+	if s.fetcher == nil {
+		return fp, errors.New("telegram is unavailable")
+	}
+
+	data, _, err := s.fetcher.Fetch(ctx, fileID)
+	if err != nil {
+		return fp, fmt.Errorf("fetch photo: %w", err)
+	}
+	fp.Hash = FingerprintData(data).Hash
+	return fp, nil
+}
+
+// PutSituationPhotos кладёт в хранилище загруженные файлы фото ситуации, сопоставляя их по file_id,
+// чтобы не скачивать те же файлы обратно из Telegram
+func (s *PhotoService) PutSituationPhotos(ctx context.Context, situationID int, files []PhotoFile) error {
+	situation, err := s.repo.GetByID(ctx, situationID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range situation.Photos {
+		photo := &situation.Photos[i]
+		for _, f := range files {
+			if f.FileID != "" && f.FileID == photo.FileID {
+				errs = append(errs, s.PutPhoto(ctx, photo, f.Data, f.Name))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PutPhoto кладёт содержимое фото в хранилище и запоминает ключ в базе;
+// расширение файла берётся из name
+func (s *PhotoService) PutPhoto(ctx context.Context, photo *domain.Photo, data []byte, name string) error {
+	contentType := http.DetectContentType(data)
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" {
+		ext = ".jpg"
+	}
+	key := fmt.Sprintf("situations/%d/%d%s", photo.SituationID, photo.ID, ext)
+
+	if err := s.store.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("store photo %d: %w", photo.ID, err)
+	}
+	if err := s.repo.SetPhotoStorage(ctx, photo.ID, key, contentType); err != nil {
+		return err
+	}
+
+	photo.StorageKey = key
+	photo.ContentType = contentType
+
+	// Хеш для поиска похожих фото; WebP и повреждённые файлы остаются без него
+	if fp := FingerprintData(data); fp.Hash != 0 && fp.Hash != photo.Hash {
+		if err := s.repo.SetPhotoFingerprint(ctx, photo.ID, "", fp.Hash); err != nil {
+			return err
+		}
+		photo.Hash = fp.Hash
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"math"
+)
+
+// MaxBaseScore — сколько BazuCoin за ход можно дать до умножения на сложность
+const MaxBaseScore = 3.0
+
+// MaxScoreRule — сколько значений может быть в правиле очков комнаты
+const MaxScoreRule = 10
+
+// DefaultScoreRule — правило очков режима угадывания в чатах и подсказка в форме веб-комнаты:
+// 3 BazuCoin за ответ с первого фото и на 0.5 меньше за каждое следующее
+var DefaultScoreRule = []float64{3, 2.5, 2, 1.5, 1}
+
+var ErrInvalidScoreRule = errors.New("правило очков — от 1 до 10 значений из 0, 0.5, 1, 1.5, 2, 2.5, 3")
+
+// ValidBaseScore сообщает, можно ли дать за ход столько BazuCoin: от 0 до 3 с шагом 0.5
+func ValidBaseScore(score float64) bool {
+	return score >= 0 && score <= MaxBaseScore && math.Mod(score*2, 1) == 0
+}
+
+// CheckScoreRule проверяет правило очков; пустое правило — очки выбирает судья
+func CheckScoreRule(rule []float64) error {
+	if len(rule) > MaxScoreRule {
+		return ErrInvalidScoreRule
+	}
+	for _, score := range rule {
+		if !ValidBaseScore(score) {
+			return ErrInvalidScoreRule
+		}
+	}
+	return nil
+}
+
+// RuleScore возвращает BazuCoin по правилу за ответ, угаданный после photos открытых фото.
+// Если фото больше, чем значений в правиле, действует последнее значение.
+func RuleScore(rule []float64, photos int) (float64, bool) {
+	if len(rule) == 0 || photos < 1 {
+		return 0, false
+	}
+	return rule[min(photos, len(rule))-1], true
+}
+
+// SuggestedScore возвращает BazuCoin, которые правило комнаты code предлагает за текущий ход,
+// и сколько фото было открыто; ok = false, если правила нет или раунд не идёт
+func (s *GameService) SuggestedScore(code string) (score float64, photos int, ok bool) {
+	code = NormalizeCode(code)
+
+	s.sessionsMu.RLock()
+	var rule []float64
+	if session := s.sessions[code]; session != nil {
+		rule = session.ScoreRule
+	}
+	s.sessionsMu.RUnlock()
+
+	photos, started := s.roundPhotos(SessionKey(code))
+	if !started {
+		return 0, 0, false
+	}
+	score, ok = RuleScore(rule, photos)
+	return score, photos, ok
+}
+
+// roundPhotos возвращает, сколько фото открыто в текущем раунде игры key
+func (s *GameService) roundPhotos(key string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil {
+		return 0, false
+	}
+	return state.CurrentPhotoIdx + 1, true
+}
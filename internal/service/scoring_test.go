@@ -0,0 +1,75 @@
+package service
+
+import "testing"
+
+func TestValidBaseScore(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  bool
+	}{
+		{0, true},
+		{0.5, true},
+		{2.5, true},
+		{MaxBaseScore, true},
+		{-0.5, false},
+		{3.5, false},
+		{1.25, false},
+		{0.1, false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidBaseScore(tt.score); got != tt.want {
+			t.Errorf("ValidBaseScore(%v) = %v, want %v", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestCheckScoreRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    []float64
+		wantErr bool
+	}{
+		{"пустое правило", nil, false},
+		{"по умолчанию", DefaultScoreRule, false},
+		{"десять значений", []float64{3, 3, 3, 3, 3, 3, 3, 3, 3, 3}, false},
+		{"больше десяти значений", []float64{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3}, true},
+		{"больше 3", []float64{4}, true},
+		{"не кратно 0.5", []float64{3, 2.2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckScoreRule(tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("CheckScoreRule(%v) = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   []float64
+		photos int
+		want   float64
+		wantOK bool
+	}{
+		{"первое фото", DefaultScoreRule, 1, 3, true},
+		{"третье фото", DefaultScoreRule, 3, 2, true},
+		{"последнее значение", DefaultScoreRule, 5, 1, true},
+		{"фото больше, чем значений", DefaultScoreRule, 8, 1, true},
+		{"одно значение", []float64{2}, 4, 2, true},
+		{"нет правила", nil, 1, 0, false},
+		{"фото не открыты", DefaultScoreRule, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RuleScore(tt.rule, tt.photos)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RuleScore(%v, %d) = %v, %v, want %v, %v", tt.rule, tt.photos, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,563 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var (
+	ErrNoActiveSession = errors.New("нет активной сессии")
+	ErrSessionNotFound = errors.New("сессия не найдена")
+)
+
+type TurnEndEvent struct {
+	PlayerName  string
+	TeamName    string // команда игрока в командной игре
+	Hints       int    // сколько подсказок открыто за ход
+	SessionID   string
+	SessionCode string
+	Difficulty  int
+	PhotosShown int
+	Suggested   *float64 // BazuCoin по правилу комнаты; nil — правила нет
+}
+
+// SessionSettings — настройки веб-комнаты, выбранные при её создании
+type SessionSettings struct {
+	Filter     domain.SituationFilter // колоды и сложности, из которых берутся ситуации
+	PhotoTimer time.Duration          // через сколько открывается следующее фото; 0 — без таймера
+	ScoreRule  []float64              // BazuCoin по числу открытых фото; пусто — очки выбирает судья
+
+	// Командная игра: вместо отдельных игроков — команды, ход переходит от команды к команде.
+	// RotateMembers — внутри команды отвечают по очереди, иначе всегда первый участник (капитан).
+	Teams         []TeamSetup
+	RotateMembers bool
+}
+
+// TeamSetup — команда и её участники при создании комнаты
+type TeamSetup struct {
+	Name    string
+	Members []string
+}
+
+// ScoreResult — начисление BazuCoin за ход с учётом сложности ситуации
+type ScoreResult struct {
+	Player     *domain.Player
+	Team       *domain.Team // команда игрока в командной игре
+	TeamScore  float64      // BazuCoin команды после начисления
+	Hints      int          // открытые за ход подсказки
+	Penalty    float64      // сколько вычтено из Base за подсказки
+	Base       float64
+	Multiplier float64
+	Awarded    float64
+}
+
+// LoadSessions восстанавливает активные сессии из базы после перезапуска
+func (s *GameService) LoadSessions(ctx context.Context) error {
+	sessions, err := s.sessionRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	for _, session := range sessions {
+		s.sessions[session.Code] = session
+	}
+
+	return nil
+}
+
+// CreateSession создаёт веб-комнату с игроками playerNames и настройками settings;
+// в командной игре (settings.Teams) игроки берутся из команд, а playerNames не используется
+func (s *GameService) CreateSession(ctx context.Context, playerNames []string, settings SessionSettings) (*domain.GameSession, error) {
+	if err := checkPhotoTimer(settings.PhotoTimer); err != nil {
+		return nil, err
+	}
+	if err := CheckScoreRule(settings.ScoreRule); err != nil {
+		return nil, err
+	}
+
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	var players []domain.Player
+	var teams []domain.Team
+	if len(settings.Teams) > 0 {
+		teams, players = newTeams(settings.Teams)
+	} else {
+		players = newPlayers(playerNames)
+	}
+
+	code := generateCode()
+	for s.sessions[code] != nil {
+		code = generateCode()
+	}
+
+	session := &domain.GameSession{
+		ID:              generateID(),
+		Code:            code,
+		Players:         players,
+		Teams:           teams,
+		RotateMembers:   settings.RotateMembers && len(teams) > 0,
+		CurrentPlayerID: players[0].ID,
+		CurrentRound:    1,
+		IsActive:        true,
+		IsFinished:      false,
+		DeckIDs:         settings.Filter.DeckIDs,
+		Difficulties:    settings.Filter.Difficulties,
+		Balanced:        settings.Filter.Balanced,
+		PhotoSeconds:    int(settings.PhotoTimer / time.Second),
+		ScoreRule:       settings.ScoreRule,
+		CreatedAt:       time.Now(),
+	}
+
+	if len(teams) > 0 {
+		session.CurrentPlayerID = teamTurnPlayer(session, session.CurrentRound).ID
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.sessions[code] = session
+
+	return session, nil
+}
+
+// newPlayers создаёт игроков в случайном порядке ходов
+func newPlayers(names []string) []domain.Player {
+	players := make([]domain.Player, len(names))
+	for i, name := range names {
+		players[i] = domain.Player{
+			ID:    generateID(),
+			Name:  name,
+			Score: 0,
+			Order: i,
+		}
+	}
+
+	rand.Shuffle(len(players), func(i, j int) {
+		players[i], players[j] = players[j], players[i]
+		players[i].Order = i
+		players[j].Order = j
+	})
+
+	return players
+}
+
+// newTeams создаёт команды в случайном порядке ходов; участники идут в порядке ввода, первый — капитан
+func newTeams(setups []TeamSetup) ([]domain.Team, []domain.Player) {
+	setups = append([]TeamSetup(nil), setups...)
+	rand.Shuffle(len(setups), func(i, j int) {
+		setups[i], setups[j] = setups[j], setups[i]
+	})
+
+	teams := make([]domain.Team, len(setups))
+	var players []domain.Player
+	for i, setup := range setups {
+		teams[i] = domain.Team{
+			ID:    generateID(),
+			Name:  setup.Name,
+			Order: i,
+		}
+		for _, name := range setup.Members {
+			players = append(players, domain.Player{
+				ID:     generateID(),
+				Name:   name,
+				Order:  len(players),
+				TeamID: teams[i].ID,
+			})
+		}
+	}
+
+	return teams, players
+}
+
+func (s *GameService) GetSession(code string) (*domain.GameSession, error) {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	session := s.sessions[NormalizeCode(code)]
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *GameService) GetCurrentPlayer(code string) *domain.Player {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	session := s.sessions[NormalizeCode(code)]
+	if session == nil {
+		return nil
+	}
+
+	return currentPlayer(session)
+}
+
+func (s *GameService) NextPlayer(ctx context.Context, code string) (*domain.Player, error) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	session := s.sessions[NormalizeCode(code)]
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	if len(session.Players) == 0 {
+		return nil, ErrNoActiveSession
+	}
+
+	nextRound := session.CurrentRound + 1
+
+	var next *domain.Player
+	if len(session.Teams) > 0 {
+		next = teamTurnPlayer(session, nextRound)
+	} else {
+		currentIdx := 0
+		for i, p := range session.Players {
+			if p.ID == session.CurrentPlayerID {
+				currentIdx = i
+				break
+			}
+		}
+		next = &session.Players[(currentIdx+1)%len(session.Players)]
+	}
+
+	if err := s.sessionRepo.UpdateTurn(ctx, session.ID, next.ID, nextRound); err != nil {
+		return nil, err
+	}
+
+	session.CurrentPlayerID = next.ID
+	session.CurrentRound = nextRound
+
+	return next, nil
+}
+
+// GetCurrentTeam возвращает команду, которая сейчас ходит; nil — если игра не командная
+func (s *GameService) GetCurrentTeam(code string) *domain.Team {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	session := s.sessions[NormalizeCode(code)]
+	if session == nil {
+		return nil
+	}
+	return playerTeam(session, currentPlayer(session))
+}
+
+// AddScoreToCurrentPlayer начисляет текущему игроку score BazuCoin, умноженные на множитель сложности ситуации раунда
+func (s *GameService) AddScoreToCurrentPlayer(ctx context.Context, code string, score float64) (*ScoreResult, error) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	code = NormalizeCode(code)
+	session := s.sessions[code]
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	player := currentPlayer(session)
+	if player == nil {
+		return nil, ErrNoActiveSession
+	}
+
+	multiplier := 1.0
+	if difficulty, ok := s.roundDifficulty(SessionKey(code)); ok {
+		multiplier = DifficultyMultiplier(difficulty)
+	}
+	hints := s.roundHints(SessionKey(code))
+	penalty := score - HintedScore(score, hints)
+	awarded := (score - penalty) * multiplier
+
+	if err := s.sessionRepo.AddScore(ctx, session.ID, player.ID, session.CurrentRound, awarded); err != nil {
+		return nil, err
+	}
+	player.Score += awarded
+
+	result := &ScoreResult{
+		Player:     player,
+		Base:       score,
+		Multiplier: multiplier,
+		Awarded:    awarded,
+		Hints:      hints,
+		Penalty:    penalty,
+	}
+	if team := playerTeam(session, player); team != nil {
+		result.Team = team
+		for _, p := range session.Players {
+			if p.TeamID == team.ID {
+				result.TeamScore += p.Score
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *GameService) GetScoreboard(code string) []domain.PlayerScore {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	session := s.sessions[NormalizeCode(code)]
+	if session == nil {
+		return nil
+	}
+
+	return sessionScoreboard(session, true)
+}
+
+// FinishGame завершает сессию и убирает её из списка активных
+func (s *GameService) FinishGame(ctx context.Context, code string) ([]domain.PlayerScore, error) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	code = NormalizeCode(code)
+	session := s.sessions[code]
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if err := s.sessionRepo.Finish(ctx, session.ID); err != nil {
+		return nil, err
+	}
+
+	session.IsActive = false
+	session.IsFinished = true
+	delete(s.sessions, code)
+
+	// Отвязываем чаты, которые управляли этой комнатой
+	for chatID, attached := range s.chatSessions {
+		if attached == code {
+			delete(s.chatSessions, chatID)
+		}
+	}
+	s.EndGame(SessionKey(code))
+
+	return sessionScoreboard(session, false), nil
+}
+
+func (s *GameService) HasActiveSession(code string) bool {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	session := s.sessions[NormalizeCode(code)]
+	return session != nil && session.IsActive
+}
+
+func (s *GameService) NotifyTurnEnd(code string) {
+	code = NormalizeCode(code)
+	difficulty, _ := s.roundDifficulty(SessionKey(code))
+	suggested, photos, hasRule := s.SuggestedScore(code)
+	hints := s.roundHints(SessionKey(code))
+
+	s.sessionsMu.RLock()
+	session := s.sessions[code]
+	var event *TurnEndEvent
+	if session != nil {
+		if player := currentPlayer(session); player != nil {
+			event = &TurnEndEvent{
+				PlayerName:  player.Name,
+				SessionID:   session.ID,
+				SessionCode: session.Code,
+				Difficulty:  difficulty,
+				PhotosShown: photos,
+				Hints:       hints,
+			}
+			if hasRule {
+				event.Suggested = &suggested
+			}
+			if team := playerTeam(session, player); team != nil {
+				event.TeamName = team.Name
+			}
+		}
+	}
+	s.sessionsMu.RUnlock()
+
+	if event == nil {
+		return
+	}
+
+	select {
+	case s.TurnEndChan <- *event:
+	default:
+	}
+}
+
+// AttachChat привязывает чат Telegram к веб-комнате: дальше кнопки в чате управляют её игрой
+func (s *GameService) AttachChat(chatID int64, code string) (*domain.GameSession, error) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	code = NormalizeCode(code)
+	session := s.sessions[code]
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	s.chatSessions[chatID] = code
+	return session, nil
+}
+
+// DetachChat возвращает чат к его собственной игре
+func (s *GameService) DetachChat(chatID int64) bool {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	_, ok := s.chatSessions[chatID]
+	delete(s.chatSessions, chatID)
+	return ok
+}
+
+// AttachedSession возвращает код комнаты, к которой привязан чат
+func (s *GameService) AttachedSession(chatID int64) (string, bool) {
+	s.sessionsMu.RLock()
+	defer s.sessionsMu.RUnlock()
+
+	code, ok := s.chatSessions[chatID]
+	return code, ok
+}
+
+// ChatsForKey возвращает чаты Telegram, которые показывают игру key:
+// сам чат или все чаты, привязанные к веб-комнате
+func (s *GameService) ChatsForKey(key string) []int64 {
+	if code, ok := sessionCodeFromKey(key); ok {
+		s.sessionsMu.RLock()
+		defer s.sessionsMu.RUnlock()
+
+		var chats []int64
+		for chatID, attached := range s.chatSessions {
+			if attached == code {
+				chats = append(chats, chatID)
+			}
+		}
+		return chats
+	}
+
+	chatID, err := strconv.ParseInt(strings.TrimPrefix(key, "chat:"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return []int64{chatID}
+}
+
+// KeyForChat возвращает ключ игры, которой управляет чат
+func (s *GameService) KeyForChat(chatID int64) string {
+	if code, ok := s.AttachedSession(chatID); ok {
+		return SessionKey(code)
+	}
+	return ChatKey(chatID)
+}
+
+// NormalizeCode приводит код комнаты к каноническому виду
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// sessionScoreboard — таблица очков по убыванию: игроки или, в командной игре, команды
+// с суммой BazuCoin участников; markCurrent отмечает того, чей сейчас ход
+func sessionScoreboard(session *domain.GameSession, markCurrent bool) []domain.PlayerScore {
+	var scores []domain.PlayerScore
+	if len(session.Teams) > 0 {
+		for _, team := range session.Teams {
+			row := domain.PlayerScore{Name: team.Name}
+			for _, p := range session.Players {
+				if p.TeamID != team.ID {
+					continue
+				}
+				row.Score += p.Score
+				row.Members = append(row.Members, p.Name)
+				row.IsCurrentPlayer = row.IsCurrentPlayer || markCurrent && p.ID == session.CurrentPlayerID
+			}
+			scores = append(scores, row)
+		}
+	} else {
+		for _, p := range session.Players {
+			scores = append(scores, domain.PlayerScore{
+				Name:            p.Name,
+				Score:           p.Score,
+				IsCurrentPlayer: markCurrent && p.ID == session.CurrentPlayerID,
+			})
+		}
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores
+}
+
+// teamTurnPlayer возвращает, кто отвечает в раунде round командной игры: команды ходят по очереди,
+// а внутри команды — капитан или, с RotateMembers, участники по очереди
+func teamTurnPlayer(session *domain.GameSession, round int) *domain.Player {
+	team := session.Teams[(round-1)%len(session.Teams)]
+
+	var members []int
+	for i, p := range session.Players {
+		if p.TeamID == team.ID {
+			members = append(members, i)
+		}
+	}
+	if len(members) == 0 {
+		return &session.Players[0]
+	}
+
+	turn := 0
+	if session.RotateMembers {
+		turn = (round - 1) / len(session.Teams) % len(members)
+	}
+	return &session.Players[members[turn]]
+}
+
+// playerTeam возвращает команду игрока; nil — если игра не командная
+func playerTeam(session *domain.GameSession, player *domain.Player) *domain.Team {
+	if player == nil || player.TeamID == "" {
+		return nil
+	}
+	for i := range session.Teams {
+		if session.Teams[i].ID == player.TeamID {
+			return &session.Teams[i]
+		}
+	}
+	return nil
+}
+
+func currentPlayer(session *domain.GameSession) *domain.Player {
+	for i := range session.Players {
+		if session.Players[i].ID == session.CurrentPlayerID {
+			return &session.Players[i]
+		}
+	}
+	return nil
+}
+
+func generateID() string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 8)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(b)
+}
+
+// generateCode создаёт короткий код комнаты без похожих символов (0/O, 1/I)
+func generateCode() string {
+	const chars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	b := make([]byte, 5)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(b)
+}
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var (
+	ErrSubmissionReviewed = errors.New("submission already reviewed")
+	ErrInvalidSubmission  = errors.New("submission needs an answer and from 1 to 5 photos")
+)
+
+// SubmissionService ведёт очередь ситуаций, предложенных игроками: предложение
+// попадает в библиотеку, только когда его одобрит администратор
+type SubmissionService struct {
+	repo       domain.SubmissionRepository
+	situations domain.SituationRepository
+	photos     *PhotoService
+}
+
+func NewSubmissionService(repo domain.SubmissionRepository, situations domain.SituationRepository, photos *PhotoService) *SubmissionService {
+	return &SubmissionService{
+		repo:       repo,
+		situations: situations,
+		photos:     photos,
+	}
+}
+
+// Submit ставит предложение в очередь модерации
+func (s *SubmissionService) Submit(ctx context.Context, submission *domain.Submission) error {
+	submission.Answer = strings.TrimSpace(submission.Answer)
+	if submission.Answer == "" || len(submission.PhotoFileIDs) == 0 || len(submission.PhotoFileIDs) > domain.MaxPhotosPerSituation {
+		return ErrInvalidSubmission
+	}
+	return s.repo.Create(ctx, submission)
+}
+
+func (s *SubmissionService) Get(ctx context.Context, id int) (*domain.Submission, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Pending возвращает предложения, ждущие модерации, от старых к новым
+func (s *SubmissionService) Pending(ctx context.Context) ([]domain.Submission, error) {
+	return s.repo.ListPending(ctx)
+}
+
+// UpdateAnswer исправляет ответ предложения до модерации
+func (s *SubmissionService) UpdateAnswer(ctx context.Context, id int, answer string) error {
+	err := s.repo.UpdateAnswer(ctx, id, strings.TrimSpace(answer))
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.notPending(ctx, id)
+	}
+	return err
+}
+
+// Approve переносит предложение в библиотеку ситуаций; у возвращённого предложения заполнен SituationID
+func (s *SubmissionService) Approve(ctx context.Context, id int, moderatorID int64) (*domain.Submission, error) {
+	submission, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if submission.Status != domain.SubmissionPending {
+		return submission, ErrSubmissionReviewed
+	}
+
+	situationID, err := s.situations.Create(ctx, submission.Answer, submission.Difficulty, submission.PhotoFileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("create situation: %w", err)
+	}
+
+	if err := s.repo.Review(ctx, id, domain.SubmissionApproved, moderatorID, situationID); err != nil {
+		// Предложение успели рассмотреть параллельно — созданная ситуация лишняя
+		if delErr := s.situations.Delete(ctx, situationID); delErr != nil {
+			log.Printf("Error deleting situation %d of submission %d: %v", situationID, id, delErr)
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return submission, ErrSubmissionReviewed
+		}
+		return nil, err
+	}
+	submission.Status = domain.SubmissionApproved
+	submission.ReviewedBy = moderatorID
+	submission.SituationID = situationID
+
+	s.saveFingerprints(ctx, submission)
+
+	// Скачиваем фото в локальное хранилище; если не вышло, они докачаются при первом показе
+	if err := s.photos.StoreSituationPhotos(ctx, situationID); err != nil {
+		log.Printf("Error storing photos of situation %d: %v", situationID, err)
+	}
+
+	return submission, nil
+}
+
+// Reject отклоняет предложение и возвращает его
+func (s *SubmissionService) Reject(ctx context.Context, id int, moderatorID int64) (*domain.Submission, error) {
+	submission, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Review(ctx, id, domain.SubmissionRejected, moderatorID, 0); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return submission, ErrSubmissionReviewed
+		}
+		return nil, err
+	}
+	submission.Status = domain.SubmissionRejected
+	submission.ReviewedBy = moderatorID
+	return submission, nil
+}
+
+// saveFingerprints запоминает file_unique_id фото новой ситуации для поиска дублей
+func (s *SubmissionService) saveFingerprints(ctx context.Context, submission *domain.Submission) {
+	uniqueIDs := make(map[string]string)
+	for i, fileID := range submission.PhotoFileIDs {
+		if i < len(submission.PhotoUniqueIDs) && submission.PhotoUniqueIDs[i] != "" {
+			uniqueIDs[fileID] = submission.PhotoUniqueIDs[i]
+		}
+	}
+	if len(uniqueIDs) == 0 {
+		return
+	}
+
+	situation, err := s.situations.GetByID(ctx, submission.SituationID)
+	if err != nil {
+		log.Printf("Error getting situation %d: %v", submission.SituationID, err)
+		return
+	}
+	for _, p := range situation.Photos {
+		if id := uniqueIDs[p.FileID]; id != "" {
+			if err := s.situations.SetPhotoFingerprint(ctx, p.ID, id, 0); err != nil {
+				log.Printf("Error saving fingerprint of photo %d: %v", p.ID, err)
+			}
+		}
+	}
+}
+
+// notPending отличает рассмотренное предложение от несуществующего
+func (s *SubmissionService) notPending(ctx context.Context, id int) error {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return err
+	}
+	return ErrSubmissionReviewed
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+// Допустимый таймер на фото
+const (
+	MinPhotoTimer = 5 * time.Second
+	MaxPhotoTimer = 10 * time.Minute
+)
+
+var ErrInvalidTimer = fmt.Errorf("таймер должен быть от %d до %d секунд", int(MinPhotoTimer/time.Second), int(MaxPhotoTimer/time.Second))
+
+// TimerEvent — таймер раунда истёк: открыто следующее фото или, после последнего, ответ
+type TimerEvent struct {
+	Key     string
+	RoundID int
+	Photo   *domain.Photo // открытое фото; nil, если открыт ответ
+	Answer  string
+}
+
+func checkPhotoTimer(d time.Duration) error {
+	if d != 0 && (d < MinPhotoTimer || d > MaxPhotoTimer) {
+		return ErrInvalidTimer
+	}
+	return nil
+}
+
+// SetDefaultTimer задаёт таймер на фото для игр, где он не выбран отдельно; 0 — без таймера
+func (s *GameService) SetDefaultTimer(d time.Duration) error {
+	if err := checkPhotoTimer(d); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaultTimer = d
+	return nil
+}
+
+// DefaultTimer возвращает таймер на фото по умолчанию
+func (s *GameService) DefaultTimer() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.defaultTimer
+}
+
+// SetTimer задаёт таймер на фото для игры в чате Telegram; веб-комнаты выбирают его при создании.
+// Таймер идущего раунда не меняется — новое значение действует со следующего раунда.
+func (s *GameService) SetTimer(key string, d time.Duration) error {
+	if err := checkPhotoTimer(d); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timers[key] = d
+	return nil
+}
+
+// Timer возвращает таймер на фото игры key; 0 — таймера нет
+func (s *GameService) Timer(key string) time.Duration {
+	if code, ok := sessionCodeFromKey(key); ok {
+		s.sessionsMu.RLock()
+		defer s.sessionsMu.RUnlock()
+
+		if session := s.sessions[code]; session != nil {
+			return time.Duration(session.PhotoSeconds) * time.Second
+		}
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if d, ok := s.timers[key]; ok {
+		return d
+	}
+	return s.defaultTimer
+}
+
+// armTimer запускает таймер открытого фото раунда заново; вызывается под s.mu
+func (s *GameService) armTimer(key string, state *GameState, d time.Duration) {
+	state.stopTimer()
+	if d <= 0 {
+		return
+	}
+
+	roundID, photoIdx := state.RoundID, state.CurrentPhotoIdx
+	state.Deadline = time.Now().Add(d)
+	state.timer = time.AfterFunc(d, func() {
+		s.timerExpired(key, roundID, photoIdx, d)
+	})
+}
+
+// timerExpired открывает следующее фото или ответ, если раунд с тех пор не сдвинули вручную
+func (s *GameService) timerExpired(key string, roundID, photoIdx int, d time.Duration) {
+	s.mu.Lock()
+	state := s.states[key]
+	if state == nil || state.CurrentSituation == nil || state.RoundID != roundID ||
+		state.CurrentPhotoIdx != photoIdx || state.AnswerShown {
+		s.mu.Unlock()
+		return
+	}
+
+	event := TimerEvent{Key: key, RoundID: roundID}
+	if photoIdx+1 < len(state.CurrentSituation.Photos) {
+		state.CurrentPhotoIdx++
+		photo := state.CurrentSituation.Photos[state.CurrentPhotoIdx]
+		event.Photo = &photo
+		s.armTimer(key, state, d)
+	} else {
+		state.stopTimer()
+		state.AnswerShown = true
+		event.Answer = state.CurrentSituation.Situation.Answer
+	}
+	s.mu.Unlock()
+
+	// Открытый по таймеру ответ завершает ход в веб-комнате так же, как кнопка
+	if event.Photo == nil {
+		if code, ok := sessionCodeFromKey(key); ok {
+			s.NotifyTurnEnd(code)
+		}
+	}
+
+	select {
+	case s.TimerChan <- event:
+	default:
+	}
+}
+
+func (state *GameState) stopTimer() {
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+	state.Deadline = time.Time{}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+)
+
+var (
+	ErrForbidden   = errors.New("forbidden")
+	ErrOwnerRole   = errors.New("owner role cannot be granted or revoked")
+	ErrInvalidRole = errors.New("invalid role")
+)
+
+// UserService хранит роли пользователей и проверяет права. Владелец задаётся
+// в конфигурации (ADMIN_ID) и не может потерять права.
+type UserService struct {
+	repo    domain.UserRepository
+	ownerID int64
+}
+
+func NewUserService(repo domain.UserRepository, ownerID int64) *UserService {
+	return &UserService{
+		repo:    repo,
+		ownerID: ownerID,
+	}
+}
+
+// EnsureOwner записывает владельца в базу, чтобы он был виден в списке ролей
+func (s *UserService) EnsureOwner(ctx context.Context) error {
+	if s.ownerID == 0 {
+		return nil
+	}
+	return s.repo.Save(ctx, &domain.User{TelegramID: s.ownerID, Role: domain.RoleOwner})
+}
+
+// Role возвращает роль пользователя; кого нет в базе — игрок
+func (s *UserService) Role(ctx context.Context, telegramID int64) domain.Role {
+	if telegramID == s.ownerID && s.ownerID != 0 {
+		return domain.RoleOwner
+	}
+
+	user, err := s.repo.Get(ctx, telegramID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Error getting user %d: %v", telegramID, err)
+		}
+		return domain.RolePlayer
+	}
+	// Владелец — только тот, кто указан в конфигурации
+	if user.Role == domain.RoleOwner {
+		return domain.RoleAdmin
+	}
+	return user.Role
+}
+
+// Can сообщает, разрешено ли пользователю действие
+func (s *UserService) Can(ctx context.Context, telegramID int64, p domain.Permission) bool {
+	return s.Role(ctx, telegramID).Can(p)
+}
+
+// Grant выдаёт пользователю роль от имени by. Роль игрока равносильна отзыву.
+func (s *UserService) Grant(ctx context.Context, by, telegramID int64, name string, role domain.Role) error {
+	if !s.Can(ctx, by, domain.PermManageUsers) {
+		return ErrForbidden
+	}
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+	if role == domain.RoleOwner || telegramID == s.ownerID {
+		return ErrOwnerRole
+	}
+
+	if role == domain.RolePlayer {
+		err := s.repo.Delete(ctx, telegramID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	user := &domain.User{TelegramID: telegramID, Name: name, Role: role, GrantedBy: by}
+	if err := s.repo.Save(ctx, user); err != nil {
+		return fmt.Errorf("grant role: %w", err)
+	}
+	return nil
+}
+
+// Revoke снимает с пользователя роль, он становится обычным игроком.
+// Возвращает domain.ErrNotFound, если роли у него не было.
+func (s *UserService) Revoke(ctx context.Context, by, telegramID int64) error {
+	if !s.Can(ctx, by, domain.PermManageUsers) {
+		return ErrForbidden
+	}
+	if telegramID == s.ownerID {
+		return ErrOwnerRole
+	}
+	return s.repo.Delete(ctx, telegramID)
+}
+
+// List возвращает пользователей с ролями; владелец из конфигурации всегда первый
+func (s *UserService) List(ctx context.Context) ([]domain.User, error) {
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []domain.User{}
+	if s.ownerID != 0 {
+		list = append(list, domain.User{TelegramID: s.ownerID, Role: domain.RoleOwner})
+	}
+	for _, u := range users {
+		if u.TelegramID == s.ownerID {
+			list[0].Name = u.Name
+			list[0].CreatedAt = u.CreatedAt
+			continue
+		}
+		if u.Role == domain.RoleOwner {
+			u.Role = domain.RoleAdmin
+		}
+		list = append(list, u)
+	}
+	return list, nil
+}
+
+// WithPermission возвращает ID пользователей, которым разрешено действие
+func (s *UserService) WithPermission(ctx context.Context, p domain.Permission) ([]int64, error) {
+	users, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, u := range users {
+		if u.Role.Can(p) {
+			ids = append(ids, u.TelegramID)
+		}
+	}
+	return ids, nil
+}
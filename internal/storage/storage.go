@@ -0,0 +1,17 @@
+// Package storage — хранилища бинарных объектов (фотографий ситуаций).
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// BlobStore хранит объекты по ключу вида "situations/12/34.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
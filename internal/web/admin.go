@@ -0,0 +1,629 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+const (
+	// adminPageSize — сколько ситуаций отдаёт одна страница списка по умолчанию
+	adminPageSize = 20
+	// maxUploadSize — предел тела запроса с фото: до 5 фото по 10 МБ
+	maxUploadSize = domain.MaxPhotosPerSituation*10<<20 + 1<<20
+)
+
+// UploaderFunc возвращает загрузчик фото в Telegram через чат пользователя chatID
+type UploaderFunc func(chatID int64) service.PhotoUploader
+
+// AdminHandlers — JSON API админ-панели: ситуации, фото, сброс и очистка игры
+type AdminHandlers struct {
+	game     *service.GameService
+	repo     domain.SituationRepository
+	decks    domain.DeckRepository
+	photos   *service.PhotoService
+	users    *service.UserService
+	auth     *AdminAuth
+	uploader UploaderFunc
+}
+
+func NewAdminHandlers(game *service.GameService, repo domain.SituationRepository, decks domain.DeckRepository, photos *service.PhotoService, users *service.UserService, auth *AdminAuth, uploader UploaderFunc) *AdminHandlers {
+	return &AdminHandlers{
+		game:     game,
+		repo:     repo,
+		decks:    decks,
+		photos:   photos,
+		users:    users,
+		auth:     auth,
+		uploader: uploader,
+	}
+}
+
+type AdminPhoto struct {
+	ID        int    `json:"id"`
+	URL       string `json:"url"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+type AdminSituation struct {
+	ID         int          `json:"id"`
+	Answer     string       `json:"answer"`
+	Aliases    []string     `json:"aliases,omitempty"`
+	Hints      []string     `json:"hints,omitempty"`
+	Difficulty int          `json:"difficulty"`
+	Used       bool         `json:"used"`
+	InPlay     bool         `json:"inPlay"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	PhotoCount int          `json:"photoCount"`
+	Decks      []int        `json:"decks,omitempty"`
+	Photos     []AdminPhoto `json:"photos,omitempty"`
+}
+
+type AdminListResponse struct {
+	Success    bool             `json:"success"`
+	Situations []AdminSituation `json:"situations"`
+	Total      int              `json:"total"`
+}
+
+type AdminSituationResponse struct {
+	Success   bool            `json:"success"`
+	Message   string          `json:"message,omitempty"`
+	Situation *AdminSituation `json:"situation,omitempty"`
+}
+
+type AdminMeResponse struct {
+	Success bool        `json:"success"`
+	ID      int64       `json:"id"`
+	Role    domain.Role `json:"role"`
+}
+
+type UpdateSituationRequest struct {
+	Answer     *string   `json:"answer"`
+	Aliases    *[]string `json:"aliases"`
+	Hints      *[]string `json:"hints"`
+	Difficulty *int      `json:"difficulty"`
+	Decks      *[]int    `json:"decks"`
+	Used       *bool     `json:"used"`
+}
+
+type ReorderPhotosRequest struct {
+	PhotoIDs []int `json:"photoIds"`
+}
+
+func (h *AdminHandlers) Me(w http.ResponseWriter, r *http.Request) {
+	userID := adminUser(r.Context())
+	h.jsonResponse(w, AdminMeResponse{
+		Success: true,
+		ID:      userID,
+		Role:    h.users.Role(r.Context(), userID),
+	})
+}
+
+// ListSituations — страница ситуаций; ?q= ищет по ответу или номеру
+func (h *AdminHandlers) ListSituations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = adminPageSize
+	}
+
+	summaries, total, err := h.repo.Search(r.Context(), q.Get("q"), max(offset, 0), limit)
+	if err != nil {
+		log.Printf("Error listing situations: %v", err)
+		h.errorResponse(w, "Ошибка получения списка ситуаций", http.StatusInternalServerError)
+		return
+	}
+
+	resp := AdminListResponse{Success: true, Situations: []AdminSituation{}, Total: total}
+	for _, s := range summaries {
+		resp.Situations = append(resp.Situations, AdminSituation{
+			ID:         s.ID,
+			Answer:     s.Answer,
+			Difficulty: s.Difficulty,
+			Used:       s.IsUsed,
+			InPlay:     h.game.IsSituationInPlay(s.ID),
+			CreatedAt:  s.CreatedAt,
+			PhotoCount: s.PhotoCount,
+		})
+	}
+
+	h.jsonResponse(w, resp)
+}
+
+func (h *AdminHandlers) GetSituation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	h.situationResponse(w, r, id, "")
+}
+
+// CreateSituation создаёт ситуацию из multipart-формы: answer, aliases, hints, difficulty, decks, photos (файлы)
+func (h *AdminHandlers) CreateSituation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		h.errorResponse(w, "Слишком большой запрос или неверный формат формы", http.StatusBadRequest)
+		return
+	}
+
+	situation := domain.NewSituation{
+		Answer:     strings.TrimSpace(r.FormValue("answer")),
+		Difficulty: domain.DifficultyMedium,
+	}
+	if situation.Answer == "" {
+		h.errorResponse(w, "Укажите ответ", http.StatusBadRequest)
+		return
+	}
+	situation.Aliases = domain.CleanAliases(situation.Answer, r.MultipartForm.Value["aliases"])
+	if len(situation.Aliases) > domain.MaxAliases {
+		h.errorResponse(w, fmt.Sprintf("Не больше %d синонимов ответа", domain.MaxAliases), http.StatusBadRequest)
+		return
+	}
+	situation.Hints = domain.CleanHints(r.MultipartForm.Value["hints"])
+	if len(situation.Hints) > domain.MaxHints {
+		h.errorResponse(w, fmt.Sprintf("Не больше %d подсказок", domain.MaxHints), http.StatusBadRequest)
+		return
+	}
+	if v := r.FormValue("difficulty"); v != "" {
+		situation.Difficulty, _ = strconv.Atoi(v)
+		if !slices.Contains(domain.Difficulties, situation.Difficulty) {
+			h.errorResponse(w, "Неизвестная сложность", http.StatusBadRequest)
+			return
+		}
+	}
+	for _, v := range r.MultipartForm.Value["decks"] {
+		deckID, err := strconv.Atoi(v)
+		if err != nil {
+			h.errorResponse(w, "Колода не найдена", http.StatusBadRequest)
+			return
+		}
+		situation.DeckIDs = append(situation.DeckIDs, deckID)
+	}
+	if !h.checkDecks(w, ctx, situation.DeckIDs) {
+		return
+	}
+
+	files, ok := h.readPhotos(w, r.MultipartForm.File["photos"], 0)
+	if !ok {
+		return
+	}
+	if len(files) == 0 {
+		h.errorResponse(w, "Добавьте хотя бы одно фото", http.StatusBadRequest)
+		return
+	}
+
+	if err := service.UploadPhotos(ctx, h.uploader(adminUser(ctx)), files); err != nil {
+		log.Printf("Error uploading photos: %v", err)
+		h.errorResponse(w, "Не удалось загрузить фото в Telegram", http.StatusBadGateway)
+		return
+	}
+	for _, f := range files {
+		situation.PhotoFileIDs = append(situation.PhotoFileIDs, f.FileID)
+	}
+
+	ids, err := h.repo.CreateBatch(ctx, []domain.NewSituation{situation})
+	if err != nil {
+		log.Printf("Error creating situation: %v", err)
+		h.errorResponse(w, "Ошибка сохранения ситуации", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.photos.PutSituationPhotos(ctx, ids[0], files); err != nil {
+		log.Printf("Error storing photos of situation %d: %v", ids[0], err)
+	}
+
+	h.situationResponse(w, r, ids[0], "Ситуация добавлена")
+}
+
+// UpdateSituation меняет переданные поля ситуации
+func (h *AdminHandlers) UpdateSituation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateSituationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	update := domain.SituationUpdate{Difficulty: req.Difficulty, DeckIDs: req.Decks, IsUsed: req.Used}
+
+	if req.Answer != nil {
+		answer := strings.TrimSpace(*req.Answer)
+		if answer == "" {
+			h.errorResponse(w, "Ответ не может быть пустым", http.StatusBadRequest)
+			return
+		}
+		update.Answer = &answer
+	}
+	if req.Aliases != nil {
+		aliases, err := h.cleanAliases(ctx, id, update.Answer, *req.Aliases)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				h.errorResponse(w, "Ситуация не найдена", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting situation %d: %v", id, err)
+			h.errorResponse(w, "Ошибка сохранения ситуации", http.StatusInternalServerError)
+			return
+		}
+		if len(aliases) > domain.MaxAliases {
+			h.errorResponse(w, fmt.Sprintf("Не больше %d синонимов ответа", domain.MaxAliases), http.StatusBadRequest)
+			return
+		}
+		update.Aliases = &aliases
+	}
+	if req.Hints != nil {
+		hints := domain.CleanHints(*req.Hints)
+		if len(hints) > domain.MaxHints {
+			h.errorResponse(w, fmt.Sprintf("Не больше %d подсказок", domain.MaxHints), http.StatusBadRequest)
+			return
+		}
+		update.Hints = &hints
+	}
+	if req.Difficulty != nil && !slices.Contains(domain.Difficulties, *req.Difficulty) {
+		h.errorResponse(w, "Неизвестная сложность", http.StatusBadRequest)
+		return
+	}
+	if req.Decks != nil && !h.checkDecks(w, ctx, *req.Decks) {
+		return
+	}
+
+	if err := h.repo.Update(ctx, id, update); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Ситуация не найдена", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error updating situation %d: %v", id, err)
+		h.errorResponse(w, "Ошибка сохранения ситуации", http.StatusInternalServerError)
+		return
+	}
+
+	h.situationResponse(w, r, id, "Сохранено")
+}
+
+func (h *AdminHandlers) DeleteSituation(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.photos.DeleteSituation(r.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Ситуация не найдена", http.StatusNotFound)
+			return
+		}
+		// Ситуация уже удалена из базы, не удалось убрать только файлы
+		log.Printf("Error deleting situation %d: %v", id, err)
+	}
+
+	h.jsonResponse(w, AdminSituationResponse{Success: true, Message: "Ситуация удалена"})
+}
+
+// UploadPhotos добавляет к ситуации фото из multipart-поля photos
+func (h *AdminHandlers) UploadPhotos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		h.errorResponse(w, "Слишком большой запрос или неверный формат формы", http.StatusBadRequest)
+		return
+	}
+
+	// Ситуацию проверяем до загрузки в Telegram, чтобы не загружать фото впустую
+	situation, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Ситуация не найдена", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error getting situation %d: %v", id, err)
+		h.errorResponse(w, "Ошибка сохранения фото", http.StatusInternalServerError)
+		return
+	}
+
+	files, ok := h.readPhotos(w, r.MultipartForm.File["photos"], len(situation.Photos))
+	if !ok {
+		return
+	}
+	if len(files) == 0 {
+		h.errorResponse(w, "Выберите фото", http.StatusBadRequest)
+		return
+	}
+
+	if err := service.UploadPhotos(ctx, h.uploader(adminUser(ctx)), files); err != nil {
+		log.Printf("Error uploading photos: %v", err)
+		h.errorResponse(w, "Не удалось загрузить фото в Telegram", http.StatusBadGateway)
+		return
+	}
+
+	fileIDs := make([]string, len(files))
+	for i, f := range files {
+		fileIDs[i] = f.FileID
+	}
+	if _, err := h.repo.AddPhotos(ctx, id, fileIDs); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Ситуация не найдена", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error adding photos to situation %d: %v", id, err)
+		h.errorResponse(w, "Ошибка сохранения фото", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.photos.PutSituationPhotos(ctx, id, files); err != nil {
+		log.Printf("Error storing photos of situation %d: %v", id, err)
+	}
+
+	h.situationResponse(w, r, id, "Фото добавлены")
+}
+
+// ReorderPhotos задаёт порядок показа фото ситуации
+func (h *AdminHandlers) ReorderPhotos(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, "Неверный формат запроса", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.ReorderPhotos(r.Context(), id, req.PhotoIDs); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Нужно перечислить все фото этой ситуации", http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error reordering photos of situation %d: %v", id, err)
+		h.errorResponse(w, "Ошибка сохранения порядка фото", http.StatusInternalServerError)
+		return
+	}
+
+	h.situationResponse(w, r, id, "Порядок фото сохранён")
+}
+
+func (h *AdminHandlers) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	photoID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	photo, err := h.repo.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Фото уже удалено", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error getting photo %d: %v", photoID, err)
+		h.errorResponse(w, "Ошибка удаления фото", http.StatusInternalServerError)
+		return
+	}
+
+	count, err := h.repo.CountPhotos(ctx, photo.SituationID)
+	if err == nil && count <= 1 {
+		h.errorResponse(w, "Нельзя удалить последнее фото. Удалите ситуацию целиком или сначала добавьте другое фото", http.StatusConflict)
+		return
+	}
+
+	if err := h.photos.DeletePhoto(ctx, photoID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Фото уже удалено", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error deleting photo %d: %v", photoID, err)
+	}
+
+	h.situationResponse(w, r, photo.SituationID, "Фото удалено")
+}
+
+// ServePhoto отдаёт любое фото библиотеки, в отличие от игровых ссылок — без подписи
+func (h *AdminHandlers) ServePhoto(w http.ResponseWriter, r *http.Request) {
+	photoID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	file, photo, err := h.photos.Open(r.Context(), photoID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error opening photo %d: %v", photoID, err)
+		http.Error(w, "Error loading file", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	contentType := photo.ContentType
+	if contentType == "" {
+		contentType = "image/jpeg"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	http.ServeContent(w, r, "", photo.CreatedAt, file)
+}
+
+// Reset делает все ситуации снова доступными
+func (h *AdminHandlers) Reset(w http.ResponseWriter, r *http.Request) {
+	if err := h.game.ResetGame(r.Context()); err != nil {
+		log.Printf("Error resetting game: %v", err)
+		h.errorResponse(w, "Ошибка сброса игры", http.StatusInternalServerError)
+		return
+	}
+
+	h.jsonResponse(w, AdminSituationResponse{Success: true, Message: "Игра сброшена, все ситуации снова доступны"})
+}
+
+// DeleteAll удаляет все ситуации и фото
+func (h *AdminHandlers) DeleteAll(w http.ResponseWriter, r *http.Request) {
+	count, err := h.photos.DeleteAll(r.Context())
+	if err != nil {
+		log.Printf("Error deleting all: %v", err)
+		h.errorResponse(w, "Ошибка удаления данных", http.StatusInternalServerError)
+		return
+	}
+
+	h.game.ResetGame(r.Context())
+
+	h.jsonResponse(w, AdminSituationResponse{Success: true, Message: "Удалено ситуаций: " + strconv.Itoa(count)})
+}
+
+// readPhotos читает загруженные файлы; у ситуации уже есть existing фото
+func (h *AdminHandlers) readPhotos(w http.ResponseWriter, headers []*multipart.FileHeader, existing int) ([]service.PhotoFile, bool) {
+	if existing+len(headers) > domain.MaxPhotosPerSituation {
+		h.errorResponse(w, "У ситуации может быть не больше "+strconv.Itoa(domain.MaxPhotosPerSituation)+" фото", http.StatusBadRequest)
+		return nil, false
+	}
+
+	var files []service.PhotoFile
+	for _, fh := range headers {
+		f, err := fh.Open()
+		if err != nil {
+			h.errorResponse(w, "Не удалось прочитать "+fh.Filename, http.StatusBadRequest)
+			return nil, false
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			h.errorResponse(w, "Не удалось прочитать "+fh.Filename, http.StatusBadRequest)
+			return nil, false
+		}
+
+		file := service.PhotoFile{Name: fh.Filename, Data: data}
+		if err := service.CheckPhotoFile(file); err != nil {
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		files = append(files, file)
+	}
+	return files, true
+}
+
+// situationResponse отдаёт ситуацию со ссылками на фото и колодами
+func (h *AdminHandlers) situationResponse(w http.ResponseWriter, r *http.Request, id int, message string) {
+	ctx := r.Context()
+
+	situation, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			h.errorResponse(w, "Ситуация не найдена", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error getting situation %d: %v", id, err)
+		h.errorResponse(w, "Ошибка получения ситуации", http.StatusInternalServerError)
+		return
+	}
+
+	deckIDs, err := h.repo.GetDeckIDs(ctx, id)
+	if err != nil {
+		log.Printf("Error getting decks of situation %d: %v", id, err)
+	}
+
+	resp := &AdminSituation{
+		ID:         situation.Situation.ID,
+		Answer:     situation.Situation.Answer,
+		Aliases:    situation.Situation.Aliases,
+		Hints:      situation.Situation.Hints,
+		Difficulty: situation.Situation.Difficulty,
+		Used:       situation.Situation.IsUsed,
+		InPlay:     h.game.IsSituationInPlay(id),
+		CreatedAt:  situation.Situation.CreatedAt,
+		PhotoCount: len(situation.Photos),
+		Decks:      deckIDs,
+	}
+	for _, p := range situation.Photos {
+		resp.Photos = append(resp.Photos, AdminPhoto{
+			ID:        p.ID,
+			URL:       "/api/admin/photos/" + strconv.Itoa(p.ID),
+			SortOrder: p.SortOrder,
+		})
+	}
+
+	h.jsonResponse(w, AdminSituationResponse{Success: true, Message: message, Situation: resp})
+}
+
+// cleanAliases очищает синонимы ответа; answer — новый ответ, если он меняется в том же запросе
+func (h *AdminHandlers) cleanAliases(ctx context.Context, id int, answer *string, aliases []string) ([]string, error) {
+	if answer == nil {
+		situation, err := h.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		answer = &situation.Situation.Answer
+	}
+	return domain.CleanAliases(*answer, aliases), nil
+}
+
+// checkDecks проверяет, что все колоды существуют
+func (h *AdminHandlers) checkDecks(w http.ResponseWriter, ctx context.Context, deckIDs []int) bool {
+	if len(deckIDs) == 0 {
+		return true
+	}
+
+	decks, err := h.decks.List(ctx)
+	if err != nil {
+		log.Printf("Error listing decks: %v", err)
+		h.errorResponse(w, "Ошибка получения колод", http.StatusInternalServerError)
+		return false
+	}
+	for _, id := range deckIDs {
+		if !slices.ContainsFunc(decks, func(d domain.Deck) bool { return d.ID == id }) {
+			h.errorResponse(w, "Колода не найдена", http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+func (h *AdminHandlers) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.errorResponse(w, "Неверный номер", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *AdminHandlers) jsonResponse(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AdminHandlers) errorResponse(w http.ResponseWriter, message string, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(AdminSituationResponse{
+		Success: false,
+		Message: message,
+	})
+}
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
+)
+
+const (
+	// adminCookie — cookie сессии админ-панели
+	adminCookie = "quiz_admin"
+
+	// loginLinkTTL — сколько действует ссылка входа, присланная ботом
+	loginLinkTTL = 10 * time.Minute
+	// adminSessionTTL — сколько живёт сессия админ-панели
+	adminSessionTTL = 12 * time.Hour
+)
+
+type adminUserKey struct{}
+
+// AdminAuth пускает в админ-панель по ссылке, которую бот присылает командой /web.
+// Ссылка и cookie сессии подписаны; права проверяются по роли на каждом запросе,
+// поэтому отзыв роли сразу закрывает доступ.
+type AdminAuth struct {
+	secret  []byte
+	baseURL string
+	users   *service.UserService
+}
+
+func NewAdminAuth(secret []byte, baseURL string, users *service.UserService) *AdminAuth {
+	return &AdminAuth{
+		secret:  secret,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		users:   users,
+	}
+}
+
+// LoginURL возвращает ссылку входа в админ-панель для пользователя Telegram
+func (a *AdminAuth) LoginURL(userID int64, now time.Time) string {
+	exp := now.Add(loginLinkTTL).Unix()
+
+	q := url.Values{}
+	q.Set("u", strconv.FormatInt(userID, 10))
+	q.Set("exp", strconv.FormatInt(exp, 10))
+	q.Set("sig", a.signature("login", userID, exp))
+	return a.baseURL + "/admin/login?" + q.Encode()
+}
+
+// Login проверяет ссылку входа, ставит cookie сессии и открывает админ-панель
+func (a *AdminAuth) Login(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID, err1 := strconv.ParseInt(q.Get("u"), 10, 64)
+	exp, err2 := strconv.ParseInt(q.Get("exp"), 10, 64)
+	if err1 != nil || err2 != nil || !a.valid("login", userID, exp, q.Get("sig"), time.Now()) {
+		http.Error(w, "Ссылка недействительна или устарела. Получите новую командой /web в Telegram", http.StatusForbidden)
+		return
+	}
+	if !a.users.Can(r.Context(), userID, domain.PermManageContent) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	sessionExp := time.Now().Add(adminSessionTTL)
+	http.SetCookie(w, &http.Cookie{
+		Name:     adminCookie,
+		Value:    fmt.Sprintf("%d.%d.%s", userID, sessionExp.Unix(), a.signature("session", userID, sessionExp.Unix())),
+		Path:     "/",
+		Expires:  sessionExp,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/admin.html", http.StatusSeeOther)
+}
+
+// Logout удаляет cookie сессии
+func (a *AdminAuth) Logout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     adminCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Require пропускает запрос, только если в cookie действующая сессия пользователя с правом p
+func (a *AdminAuth) Require(p domain.Permission, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := a.sessionUser(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !a.users.Can(r.Context(), userID, p) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		handler(w, r.WithContext(context.WithValue(r.Context(), adminUserKey{}, userID)))
+	}
+}
+
+// adminUser возвращает ID пользователя Telegram, вошедшего в админ-панель
+func adminUser(ctx context.Context) int64 {
+	userID, _ := ctx.Value(adminUserKey{}).(int64)
+	return userID
+}
+
+func (a *AdminAuth) sessionUser(r *http.Request) (int64, bool) {
+	cookie, err := r.Cookie(adminCookie)
+	if err != nil {
+		return 0, false
+	}
+
+	parts := strings.SplitN(cookie.Value, ".", 3)
+	if len(parts) != 3 {
+		return 0, false
+	}
+	userID, err1 := strconv.ParseInt(parts[0], 10, 64)
+	exp, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil || !a.valid("session", userID, exp, parts[2], time.Now()) {
+		return 0, false
+	}
+	return userID, true
+}
+
+func (a *AdminAuth) valid(purpose string, userID, exp int64, sig string, now time.Time) bool {
+	expected := a.signature(purpose, userID, exp)
+	return hmac.Equal([]byte(expected), []byte(sig)) && now.Unix() <= exp
+}
+
+func (a *AdminAuth) signature(purpose string, userID, exp int64) string {
+	mac := hmac.New(sha256.New, a.secret)
+	fmt.Fprintf(mac, "admin:%s:%d:%d", purpose, userID, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
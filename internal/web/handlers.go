@@ -4,24 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/plastinin/photo-quiz-bot/internal/domain"
-	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
 	"github.com/plastinin/photo-quiz-bot/internal/service"
 )
 
 type Handlers struct {
-	game    *service.GameService
-	repo    *postgres.SituationRepository
-	session *SessionManager
+	game   *service.GameService
+	repo   domain.SituationRepository
+	decks  domain.DeckRepository
+	signer *URLSigner
 }
 
-func NewHandlers(repo *postgres.SituationRepository, session *SessionManager) *Handlers {
+func NewHandlers(game *service.GameService, repo domain.SituationRepository, decks domain.DeckRepository, signer *URLSigner) *Handlers {
 	return &Handlers{
-		game:    service.NewGameService(repo),
-		repo:    repo,
-		session: session,
+		game:   game,
+		repo:   repo,
+		decks:  decks,
+		signer: signer,
 	}
 }
 
@@ -35,8 +41,19 @@ type GameResponse struct {
 	HasMore       bool                  `json:"hasMore"`
 	GameOver      bool                  `json:"gameOver"`
 	CurrentPlayer *domain.Player        `json:"currentPlayer,omitempty"`
+	CurrentTeam   *domain.Team          `json:"currentTeam,omitempty"` // команда, которая сейчас ходит
 	Scoreboard    []domain.PlayerScore  `json:"scoreboard,omitempty"`
 	NeedScore     bool                  `json:"needScore,omitempty"`
+	Round         int                   `json:"round,omitempty"`
+	Difficulty    int                   `json:"difficulty,omitempty"`
+	Multiplier    float64               `json:"multiplier,omitempty"`
+	PhotoURLs     []string              `json:"photoUrls,omitempty"`
+	AnswerShown   bool                  `json:"answerShown,omitempty"`
+	TimeLeftMs    int64                 `json:"timeLeftMs,omitempty"` // сколько осталось до срабатывания таймера раунда
+	SuggestedScore *float64             `json:"suggestedScore,omitempty"` // BazuCoin за ход по правилу комнаты
+	Hints         []string              `json:"hints,omitempty"`       // открытые подсказки раунда
+	TotalHints    int                   `json:"totalHints,omitempty"`
+	HintPenalty   float64               `json:"hintPenalty,omitempty"` // сколько BazuCoin подсказки вычтут из очков за ход
 }
 
 type StatsResponse struct {
@@ -50,11 +67,30 @@ type SessionResponse struct {
 	Message       string                `json:"message,omitempty"`
 	Session       *domain.GameSession   `json:"session,omitempty"`
 	CurrentPlayer *domain.Player        `json:"currentPlayer,omitempty"`
+	CurrentTeam   *domain.Team          `json:"currentTeam,omitempty"`
 	Scoreboard    []domain.PlayerScore  `json:"scoreboard,omitempty"`
 }
 
+type DeckResponse struct {
+	domain.Deck
+	Total     int `json:"total"`
+	Remaining int `json:"remaining"`
+}
+
 type CreateSessionRequest struct {
-	Players []string `json:"players"`
+	Players      []string `json:"players"`
+	Decks        []int    `json:"decks"`
+	Difficulties []int    `json:"difficulties"`
+	Balanced     bool     `json:"balanced"`
+	PhotoSeconds *int     `json:"photoSeconds"` // таймер на фото; не задан — PHOTO_TIMER, 0 — без таймера
+	ScoreRule    []float64 `json:"scoreRule"`   // BazuCoin по числу открытых фото; пусто — очки выбирает судья
+	Teams        []TeamRequest `json:"teams"`   // командная игра: вместо players
+	RotateMembers bool    `json:"rotateMembers"` // участники команды отвечают по очереди
+}
+
+type TeamRequest struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
 }
 
 func (h *Handlers) CreateSession(w http.ResponseWriter, r *http.Request) {
@@ -64,56 +100,147 @@ func (h *Handlers) CreateSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.Players) < 1 {
-		h.errorResponse(w, "Нужен хотя бы один игрок", http.StatusBadRequest)
-		return
+	if len(req.Teams) > 0 {
+		if msg := checkTeams(req.Teams); msg != "" {
+			h.errorResponse(w, msg, http.StatusBadRequest)
+			return
+		}
+		req.Players = nil
+	} else {
+		if len(req.Players) < 1 {
+			h.errorResponse(w, "Нужен хотя бы один игрок", http.StatusBadRequest)
+			return
+		}
+
+		if len(req.Players) > 10 {
+			h.errorResponse(w, "Максимум 10 игроков", http.StatusBadRequest)
+			return
+		}
+
+		for _, name := range req.Players {
+			if name == "" {
+				h.errorResponse(w, "Имя игрока не может быть пустым", http.StatusBadRequest)
+				return
+			}
+		}
 	}
 
-	if len(req.Players) > 10 {
-		h.errorResponse(w, "Максимум 10 игроков", http.StatusBadRequest)
-		return
+	if len(req.Decks) > 0 {
+		if err := h.checkDecks(r.Context(), req.Decks); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				h.errorResponse(w, "Колода не найдена", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error checking decks: %v", err)
+			h.errorResponse(w, "Ошибка создания сессии", http.StatusInternalServerError)
+			return
+		}
 	}
 
-	for _, name := range req.Players {
-		if name == "" {
-			h.errorResponse(w, "Имя игрока не может быть пустым", http.StatusBadRequest)
+	for _, level := range req.Difficulties {
+		if !slices.Contains(domain.Difficulties, level) {
+			h.errorResponse(w, "Неизвестная сложность", http.StatusBadRequest)
 			return
 		}
 	}
 
-	session := h.session.CreateSession(req.Players)
-	currentPlayer := h.session.GetCurrentPlayer()
+	photoTimer := h.game.DefaultTimer()
+	if req.PhotoSeconds != nil {
+		photoTimer = time.Duration(*req.PhotoSeconds) * time.Second
+	}
+
+	session, err := h.game.CreateSession(r.Context(), req.Players, service.SessionSettings{
+		Filter: domain.SituationFilter{
+			DeckIDs:      req.Decks,
+			Difficulties: req.Difficulties,
+			Balanced:     req.Balanced,
+		},
+		PhotoTimer:    photoTimer,
+		ScoreRule:     req.ScoreRule,
+		Teams:         teamSetups(req.Teams),
+		RotateMembers: req.RotateMembers,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimer) || errors.Is(err, service.ErrInvalidScoreRule) {
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Error creating session: %v", err)
+		h.errorResponse(w, "Ошибка создания сессии", http.StatusInternalServerError)
+		return
+	}
 
 	h.jsonResponse(w, SessionResponse{
 		Success:       true,
 		Session:       session,
-		CurrentPlayer: currentPlayer,
-		Scoreboard:    h.session.GetScoreboard(),
+		CurrentPlayer: h.game.GetCurrentPlayer(session.Code),
+		CurrentTeam:   h.game.GetCurrentTeam(session.Code),
+		Scoreboard:    h.game.GetScoreboard(session.Code),
 	})
 }
 
+// checkTeams проверяет команды комнаты; возвращает текст ошибки или пустую строку
+func checkTeams(teams []TeamRequest) string {
+	if len(teams) > 10 {
+		return "Максимум 10 команд"
+	}
+	for _, team := range teams {
+		if strings.TrimSpace(team.Name) == "" {
+			return "Название команды не может быть пустым"
+		}
+		if len(team.Members) < 1 {
+			return "В каждой команде нужен хотя бы один участник"
+		}
+		if len(team.Members) > 10 {
+			return "Максимум 10 участников в команде"
+		}
+		for _, name := range team.Members {
+			if strings.TrimSpace(name) == "" {
+				return "Имя участника не может быть пустым"
+			}
+		}
+	}
+	return ""
+}
+
+func teamSetups(teams []TeamRequest) []service.TeamSetup {
+	setups := make([]service.TeamSetup, len(teams))
+	for i, team := range teams {
+		setups[i] = service.TeamSetup{Name: strings.TrimSpace(team.Name)}
+		for _, name := range team.Members {
+			setups[i].Members = append(setups[i].Members, strings.TrimSpace(name))
+		}
+	}
+	return setups
+}
+
 func (h *Handlers) GetSession(w http.ResponseWriter, r *http.Request) {
-	session := h.session.GetSession()
-	if session == nil {
-		h.jsonResponse(w, SessionResponse{
-			Success: false,
-			Message: "Сессия не создана",
-		})
+	code, ok := h.sessionCode(w, r)
+	if !ok {
 		return
 	}
 
+	session, _ := h.game.GetSession(code)
+
 	h.jsonResponse(w, SessionResponse{
 		Success:       true,
 		Session:       session,
-		CurrentPlayer: h.session.GetCurrentPlayer(),
-		Scoreboard:    h.session.GetScoreboard(),
+		CurrentPlayer: h.game.GetCurrentPlayer(code),
+		CurrentTeam:   h.game.GetCurrentTeam(code),
+		Scoreboard:    h.game.GetScoreboard(code),
 	})
 }
 
 func (h *Handlers) EndSession(w http.ResponseWriter, r *http.Request) {
-	scoreboard := h.session.FinishGame()
-	if scoreboard == nil {
-		h.errorResponse(w, "Нет активной сессии", http.StatusBadRequest)
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+
+	scoreboard, err := h.finishSession(r.Context(), code)
+	if err != nil {
+		log.Printf("Error finishing session: %v", err)
+		h.errorResponse(w, "Ошибка завершения игры", http.StatusInternalServerError)
 		return
 	}
 
@@ -127,18 +254,23 @@ func (h *Handlers) EndSession(w http.ResponseWriter, r *http.Request) {
 func (h *Handlers) StartGame(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	if !h.session.HasActiveSession() {
-		h.jsonResponse(w, GameResponse{
-			Success: false,
-			Message: "Сначала создайте сессию с игроками",
-		})
+	code, ok := h.sessionCode(w, r)
+	if !ok {
 		return
 	}
+	key := service.SessionKey(code)
 
-	photo, err := h.game.StartNewRound(ctx)
+	// Если раунд уже идёт (например, к комнате подключился второй экран), продолжаем его
+	photo, err := h.game.GetCurrentPhoto(key)
+	if errors.Is(err, service.ErrGameNotStarted) {
+		photo, err = h.game.StartNewRound(ctx, key)
+	}
 	if err != nil {
 		if errors.Is(err, service.ErrNoSituations) {
-			scoreboard := h.session.FinishGame()
+			scoreboard, err := h.finishSession(ctx, code)
+			if err != nil {
+				log.Printf("Error finishing session: %v", err)
+			}
 			h.jsonResponse(w, GameResponse{
 				Success:    false,
 				Message:    "Нет доступных ситуаций",
@@ -151,23 +283,42 @@ func (h *Handlers) StartGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	current, total, _ := h.game.GetCurrentPhotoInfo()
+	current, total, _ := h.game.GetCurrentPhotoInfo(key)
 
-	h.jsonResponse(w, GameResponse{
+	resp := GameResponse{
 		Success:       true,
-		PhotoURL:      h.getPhotoURL(ctx, photo.FileID),
+		PhotoURL:      h.getPhotoURL(ctx, photo),
 		CurrentPhoto:  current,
 		TotalPhotos:   total,
 		HasMore:       current < total,
-		CurrentPlayer: h.session.GetCurrentPlayer(),
-		Scoreboard:    h.session.GetScoreboard(),
-	})
+		CurrentPlayer: h.game.GetCurrentPlayer(code),
+		CurrentTeam:   h.game.GetCurrentTeam(code),
+		Scoreboard:    h.game.GetScoreboard(code),
+	}
+	if snapshot, err := h.game.Snapshot(key); err == nil {
+		resp.setHints(snapshot)
+		resp.Round = snapshot.RoundID
+		resp.TimeLeftMs = timeLeft(snapshot)
+		resp.Difficulty = snapshot.Difficulty
+		resp.Multiplier = service.DifficultyMultiplier(snapshot.Difficulty)
+		for i := range snapshot.Photos {
+			resp.PhotoURLs = append(resp.PhotoURLs, h.getPhotoURL(ctx, &snapshot.Photos[i]))
+		}
+	}
+
+	h.jsonResponse(w, resp)
 }
 
 func (h *Handlers) NextPhoto(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	photo, err := h.game.NextPhoto(ctx)
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+	key := service.SessionKey(code)
+
+	photo, err := h.game.NextPhoto(ctx, key)
 	if err != nil {
 		if errors.Is(err, service.ErrNoMorePhotos) {
 			h.jsonResponse(w, GameResponse{
@@ -188,21 +339,27 @@ func (h *Handlers) NextPhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	current, total, _ := h.game.GetCurrentPhotoInfo()
+	current, total, _ := h.game.GetCurrentPhotoInfo(key)
 
 	h.jsonResponse(w, GameResponse{
 		Success:      true,
-		PhotoURL:     h.getPhotoURL(ctx, photo.FileID),
+		PhotoURL:     h.getPhotoURL(ctx, photo),
 		CurrentPhoto: current,
 		TotalPhotos:  total,
 		HasMore:      current < total,
+		TimeLeftMs:   h.timeLeft(key),
 	})
 }
 
 func (h *Handlers) ShowAnswer(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	answer, err := h.game.GetAnswer(ctx)
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+
+	answer, err := h.game.ShowAnswer(ctx, service.SessionKey(code))
 	if err != nil {
 		if errors.Is(err, service.ErrGameNotStarted) {
 			h.jsonResponse(w, GameResponse{
@@ -215,27 +372,61 @@ func (h *Handlers) ShowAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.session.NotifyTurnEnd()
-
 	h.jsonResponse(w, GameResponse{
-		Success:       true,
-		Answer:        answer,
-		NeedScore:     true,
-		CurrentPlayer: h.session.GetCurrentPlayer(),
+		Success:        true,
+		Answer:         answer,
+		NeedScore:      true,
+		CurrentPlayer:  h.game.GetCurrentPlayer(code),
+		CurrentTeam:    h.game.GetCurrentTeam(code),
+		SuggestedScore: h.suggestedScore(code),
 	})
 }
 
+// ShowHint открывает следующую подсказку раунда; каждая уменьшает BazuCoin за ход
+func (h *Handlers) ShowHint(w http.ResponseWriter, r *http.Request) {
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+	key := service.SessionKey(code)
+
+	if _, err := h.game.ShowHint(key); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoMoreHints):
+			h.jsonResponse(w, GameResponse{Success: false, Message: "Подсказок больше нет"})
+		case errors.Is(err, service.ErrAnswerShown):
+			h.jsonResponse(w, GameResponse{Success: false, Message: "Ответ уже открыт"})
+		case errors.Is(err, service.ErrGameNotStarted):
+			h.jsonResponse(w, GameResponse{Success: false, Message: "Сначала начните игру"})
+		default:
+			h.errorResponse(w, "Ошибка", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := GameResponse{Success: true}
+	if snapshot, err := h.game.Snapshot(key); err == nil {
+		resp.setHints(snapshot)
+	}
+	h.jsonResponse(w, resp)
+}
+
 func (h *Handlers) NextRound(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	nextPlayer := h.session.NextPlayer()
-
-	_ = h.game.FinishRound(ctx)
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+	key := service.SessionKey(code)
 
-	photo, err := h.game.StartNewRound(ctx)
+	photo, err := h.game.NextTurn(ctx, key)
 	if err != nil {
 		if errors.Is(err, service.ErrNoSituations) {
-			scoreboard := h.session.FinishGame()
+			scoreboard, err := h.finishSession(ctx, code)
+			if err != nil {
+				log.Printf("Error finishing session: %v", err)
+			}
 			h.jsonResponse(w, GameResponse{
 				Success:    false,
 				Message:    "Все ситуации сыграны! 🎉",
@@ -244,35 +435,126 @@ func (h *Handlers) NextRound(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		log.Printf("Error starting next turn: %v", err)
 		h.errorResponse(w, "Ошибка", http.StatusInternalServerError)
 		return
 	}
 
-	current, total, _ := h.game.GetCurrentPhotoInfo()
+	current, total, _ := h.game.GetCurrentPhotoInfo(key)
+	round, difficulty := h.roundInfo(key)
 
-	h.jsonResponse(w, GameResponse{
+	resp := GameResponse{
 		Success:       true,
-		PhotoURL:      h.getPhotoURL(ctx, photo.FileID),
+		PhotoURL:      h.getPhotoURL(ctx, photo),
 		CurrentPhoto:  current,
 		TotalPhotos:   total,
 		HasMore:       current < total,
-		CurrentPlayer: nextPlayer,
-		Scoreboard:    h.session.GetScoreboard(),
-	})
+		Round:         round,
+		Difficulty:    difficulty,
+		Multiplier:    service.DifficultyMultiplier(difficulty),
+		CurrentPlayer: h.game.GetCurrentPlayer(code),
+		CurrentTeam:   h.game.GetCurrentTeam(code),
+		Scoreboard:    h.game.GetScoreboard(code),
+		TimeLeftMs:    h.timeLeft(key),
+	}
+	if snapshot, err := h.game.Snapshot(key); err == nil {
+		resp.setHints(snapshot)
+	}
+	h.jsonResponse(w, resp)
+}
+
+// GetState отдаёт текущее состояние комнаты: по нему экран подхватывает действия,
+// сделанные из Telegram или с другого устройства
+func (h *Handlers) GetState(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+
+	resp := GameResponse{
+		Success:       true,
+		CurrentPlayer: h.game.GetCurrentPlayer(code),
+		CurrentTeam:   h.game.GetCurrentTeam(code),
+		Scoreboard:    h.game.GetScoreboard(code),
+	}
+
+	snapshot, err := h.game.Snapshot(service.SessionKey(code))
+	if err == nil {
+		resp.Round = snapshot.RoundID
+		resp.CurrentPhoto = len(snapshot.Photos)
+		resp.TotalPhotos = snapshot.TotalPhotos
+		resp.HasMore = len(snapshot.Photos) < snapshot.TotalPhotos
+		resp.Answer = snapshot.Answer
+		resp.AnswerShown = snapshot.AnswerShown
+		resp.TimeLeftMs = timeLeft(snapshot)
+		resp.setHints(snapshot)
+		if snapshot.AnswerShown {
+			resp.SuggestedScore = h.suggestedScore(code)
+		}
+		resp.Difficulty = snapshot.Difficulty
+		resp.Multiplier = service.DifficultyMultiplier(snapshot.Difficulty)
+		for i := range snapshot.Photos {
+			resp.PhotoURLs = append(resp.PhotoURLs, h.getPhotoURL(ctx, &snapshot.Photos[i]))
+		}
+	}
+
+	h.jsonResponse(w, resp)
 }
 
 func (h *Handlers) GetScoreboard(w http.ResponseWriter, r *http.Request) {
-	scoreboard := h.session.GetScoreboard()
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+
 	h.jsonResponse(w, SessionResponse{
 		Success:    true,
-		Scoreboard: scoreboard,
+		Scoreboard: h.game.GetScoreboard(code),
 	})
 }
 
 func (h *Handlers) Stats(w http.ResponseWriter, r *http.Request) {
+	h.stats(w, r, domain.SituationFilter{})
+}
+
+// SessionStats — статистика по колодам, выбранным для комнаты
+func (h *Handlers) SessionStats(w http.ResponseWriter, r *http.Request) {
+	code, ok := h.sessionCode(w, r)
+	if !ok {
+		return
+	}
+
+	h.stats(w, r, h.game.Filter(service.SessionKey(code)))
+}
+
+func (h *Handlers) ListDecks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	total, used, remaining, err := h.game.GetStats(ctx)
+	decks, err := h.decks.List(ctx)
+	if err != nil {
+		log.Printf("Error listing decks: %v", err)
+		h.errorResponse(w, "Ошибка получения колод", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]DeckResponse, 0, len(decks))
+	for _, d := range decks {
+		total, _, remaining, err := h.game.GetStats(ctx, domain.SituationFilter{DeckIDs: []int{d.ID}})
+		if err != nil {
+			log.Printf("Error getting stats of deck %d: %v", d.ID, err)
+			h.errorResponse(w, "Ошибка получения колод", http.StatusInternalServerError)
+			return
+		}
+		resp = append(resp, DeckResponse{Deck: d, Total: total, Remaining: remaining})
+	}
+
+	h.jsonResponse(w, resp)
+}
+
+func (h *Handlers) stats(w http.ResponseWriter, r *http.Request, filter domain.SituationFilter) {
+	total, used, remaining, err := h.game.GetStats(r.Context(), filter)
 	if err != nil {
 		h.errorResponse(w, "Ошибка получения статистики", http.StatusInternalServerError)
 		return
@@ -285,8 +567,84 @@ func (h *Handlers) Stats(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *Handlers) getPhotoURL(ctx context.Context, fileID string) string {
-	return "/api/photo/" + fileID
+// sessionCode достаёт код комнаты из пути и проверяет, что такая сессия существует
+func (h *Handlers) sessionCode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	code := service.NormalizeCode(r.PathValue("code"))
+	if !h.game.HasActiveSession(code) {
+		h.errorResponse(w, "Сессия не найдена", http.StatusNotFound)
+		return "", false
+	}
+	return code, true
+}
+
+// checkDecks проверяет, что все колоды существуют
+func (h *Handlers) checkDecks(ctx context.Context, deckIDs []int) error {
+	decks, err := h.decks.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int]bool, len(decks))
+	for _, d := range decks {
+		known[d.ID] = true
+	}
+	for _, id := range deckIDs {
+		if !known[id] {
+			return domain.ErrNotFound
+		}
+	}
+	return nil
+}
+
+func (h *Handlers) finishSession(ctx context.Context, code string) ([]domain.PlayerScore, error) {
+	return h.game.FinishGame(ctx, code)
+}
+
+// roundInfo возвращает номер и сложность текущего раунда
+func (h *Handlers) roundInfo(key string) (round, difficulty int) {
+	snapshot, err := h.game.Snapshot(key)
+	if err != nil {
+		return 0, 0
+	}
+	return snapshot.RoundID, snapshot.Difficulty
+}
+
+// suggestedScore — BazuCoin за текущий ход по правилу комнаты, nil — правила нет
+func (h *Handlers) suggestedScore(code string) *float64 {
+	score, _, ok := h.game.SuggestedScore(code)
+	if !ok {
+		return nil
+	}
+	return &score
+}
+
+// timeLeft — сколько миллисекунд осталось до срабатывания таймера раунда; 0 — таймера нет
+func (h *Handlers) timeLeft(key string) int64 {
+	snapshot, err := h.game.Snapshot(key)
+	if err != nil {
+		return 0
+	}
+	return timeLeft(snapshot)
+}
+
+// setHints заполняет открытые подсказки раунда и вычет за них
+func (resp *GameResponse) setHints(snapshot *service.RoundSnapshot) {
+	resp.Hints = snapshot.Hints
+	resp.TotalHints = snapshot.TotalHints
+	resp.HintPenalty = float64(len(snapshot.Hints)) * service.HintPenalty
+}
+
+func timeLeft(snapshot *service.RoundSnapshot) int64 {
+	if snapshot.Deadline.IsZero() {
+		return 0
+	}
+	return max(time.Until(snapshot.Deadline).Milliseconds(), 1)
+}
+
+// getPhotoURL возвращает подписанную ссылку на фото, действующую ограниченное время
+func (h *Handlers) getPhotoURL(ctx context.Context, photo *domain.Photo) string {
+	q := h.signer.Sign(photo.ID, photo.SituationID, time.Now())
+	return "/api/photo/" + strconv.Itoa(photo.ID) + "?" + q.Encode()
 }
 
 func (h *Handlers) jsonResponse(w http.ResponseWriter, data interface{}) {
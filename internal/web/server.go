@@ -2,58 +2,78 @@ package web
 
 import (
 	"context"
-	"fmt"
-	"io"
+	"errors"
 	"log"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/plastinin/photo-quiz-bot/internal/repository/postgres"
+	"github.com/plastinin/photo-quiz-bot/internal/domain"
+	"github.com/plastinin/photo-quiz-bot/internal/service"
 )
 
 type Server struct {
 	httpServer *http.Server
 	handlers   *Handlers
-	botAPI     *tgbotapi.BotAPI
-	Session    *SessionManager
+	game       *service.GameService
+	photos     *service.PhotoService
+	signer     *URLSigner
 }
 
-func NewServer(addr string, repo *postgres.SituationRepository, botToken string) (*Server, error) {
-	botAPI, err := tgbotapi.NewBotAPI(botToken)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create bot API for web: %w", err)
-	}
-
-	session := NewSessionManager()
-	handlers := NewHandlers(repo, session)
+func NewServer(addr string, game *service.GameService, repo domain.SituationRepository, decks domain.DeckRepository, photos *service.PhotoService, signer *URLSigner, admin *AdminHandlers) (*Server, error) {
+	handlers := NewHandlers(game, repo, decks, signer)
 
 	mux := http.NewServeMux()
 
 	s := &Server{
 		handlers: handlers,
-		botAPI:   botAPI,
-		Session:  session,
+		game:     game,
+		photos:   photos,
+		signer:   signer,
 	}
 
 	mux.HandleFunc("/api/session/create", s.methodPost(handlers.CreateSession))
-	mux.HandleFunc("/api/session", s.methodGet(handlers.GetSession))
-	mux.HandleFunc("/api/session/end", s.methodPost(handlers.EndSession))
-	mux.HandleFunc("/api/scoreboard", s.methodGet(handlers.GetScoreboard))
-	mux.HandleFunc("/api/start", s.methodPost(handlers.StartGame))
-	mux.HandleFunc("/api/next-photo", s.methodPost(handlers.NextPhoto))
-	mux.HandleFunc("/api/answer", s.methodPost(handlers.ShowAnswer))
-	mux.HandleFunc("/api/next-round", s.methodPost(handlers.NextRound))
+	mux.HandleFunc("/api/sessions/{code}", s.methodGet(handlers.GetSession))
+	mux.HandleFunc("/api/sessions/{code}/end", s.methodPost(handlers.EndSession))
+	mux.HandleFunc("/api/sessions/{code}/state", s.methodGet(handlers.GetState))
+	mux.HandleFunc("/api/sessions/{code}/scoreboard", s.methodGet(handlers.GetScoreboard))
+	mux.HandleFunc("/api/sessions/{code}/stats", s.methodGet(handlers.SessionStats))
+	mux.HandleFunc("/api/sessions/{code}/start", s.methodPost(handlers.StartGame))
+	mux.HandleFunc("/api/sessions/{code}/next-photo", s.methodPost(handlers.NextPhoto))
+	mux.HandleFunc("/api/sessions/{code}/answer", s.methodPost(handlers.ShowAnswer))
+	mux.HandleFunc("/api/sessions/{code}/hint", s.methodPost(handlers.ShowHint))
+	mux.HandleFunc("/api/sessions/{code}/next-round", s.methodPost(handlers.NextRound))
 	mux.HandleFunc("/api/stats", s.methodGet(handlers.Stats))
-	mux.HandleFunc("/api/photo/", s.servePhoto)
+	mux.HandleFunc("/api/decks", s.methodGet(handlers.ListDecks))
+	mux.HandleFunc("/api/photo/{id}", s.methodGet(s.servePhoto))
+
+	// Админ-панель: вход по ссылке из бота (/web), дальше — по cookie сессии
+	auth := admin.auth
+	manage := func(handler http.HandlerFunc) http.HandlerFunc {
+		return auth.Require(domain.PermManageContent, handler)
+	}
+	mux.HandleFunc("/admin/login", s.methodGet(auth.Login))
+	mux.HandleFunc("/api/admin/logout", s.methodPost(auth.Logout))
+	mux.HandleFunc("/api/admin/me", s.methodGet(manage(admin.Me)))
+	mux.HandleFunc("/api/admin/situations", s.methodGet(manage(admin.ListSituations)))
+	mux.HandleFunc("/api/admin/situations/create", s.methodPost(manage(admin.CreateSituation)))
+	mux.HandleFunc("/api/admin/situations/{id}", s.methodGet(manage(admin.GetSituation)))
+	mux.HandleFunc("/api/admin/situations/{id}/update", s.methodPost(manage(admin.UpdateSituation)))
+	mux.HandleFunc("/api/admin/situations/{id}/delete", s.methodPost(manage(admin.DeleteSituation)))
+	mux.HandleFunc("/api/admin/situations/{id}/photos", s.methodPost(manage(admin.UploadPhotos)))
+	mux.HandleFunc("/api/admin/situations/{id}/photos/order", s.methodPost(manage(admin.ReorderPhotos)))
+	mux.HandleFunc("/api/admin/photos/{id}", s.methodGet(manage(admin.ServePhoto)))
+	mux.HandleFunc("/api/admin/photos/{id}/delete", s.methodPost(manage(admin.DeletePhoto)))
+	mux.HandleFunc("/api/admin/reset", s.methodPost(manage(admin.Reset)))
+	mux.HandleFunc("/api/admin/delete-all", s.methodPost(manage(admin.DeleteAll)))
 	mux.Handle("/", http.FileServer(http.Dir("internal/web/static")))
 
 	s.httpServer = &http.Server{
 		Addr:         addr,
 		Handler:      mux,
 		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		// Загрузка фото из админ-панели ждёт ответа Telegram
+		WriteTimeout: 60 * time.Second,
 	}
 
 	return s, nil
@@ -68,53 +88,49 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
-func (s *Server) AddScoreToCurrentPlayer(score float64) (string, float64, bool) {
-	player := s.Session.AddScoreToCurrentPlayer(score)
-	if player == nil {
-		return "", 0, false
+func (s *Server) servePhoto(w http.ResponseWriter, r *http.Request) {
+	photoID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
 	}
-	return player.Name, player.Score, true
-}
 
-func (s *Server) GetCurrentPlayerName() string {
-	player := s.Session.GetCurrentPlayer()
-	if player == nil {
-		return ""
+	// Ссылка должна быть подписана, не истекла и относиться к ситуации, которая сейчас в игре
+	situationID, err := s.signer.Verify(photoID, r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
 	}
-	return player.Name
-}
-
-func (s *Server) HasActiveSession() bool {
-	return s.Session.HasActiveSession()
-}
-
-func (s *Server) servePhoto(w http.ResponseWriter, r *http.Request) {
-	fileID := strings.TrimPrefix(r.URL.Path, "/api/photo/")
-	if fileID == "" {
-		http.Error(w, "File ID required", http.StatusBadRequest)
+	if !s.game.IsSituationInPlay(situationID) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	fileConfig := tgbotapi.FileConfig{FileID: fileID}
-	file, err := s.botAPI.GetFile(fileConfig)
+	file, photo, err := s.photos.Open(r.Context(), photoID)
 	if err != nil {
-		log.Printf("Error getting file from Telegram: %v", err)
-		http.Error(w, "File not found", http.StatusNotFound)
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Error opening photo %d: %v", photoID, err)
+		http.Error(w, "Error loading file", http.StatusInternalServerError)
 		return
 	}
+	defer file.Close()
 
-	fileURL := file.Link(s.botAPI.Token)
-	resp, err := http.Get(fileURL)
-	if err != nil {
-		log.Printf("Error downloading file: %v", err)
-		http.Error(w, "Error downloading file", http.StatusInternalServerError)
+	if photo.SituationID != situationID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
-	defer resp.Body.Close()
 
-	w.Header().Set("Content-Type", "image/jpeg")
-	w.Header().Set("Cache-Control", "public, max-age=86400")
-	io.Copy(w, resp.Body)
+	contentType := photo.ContentType
+	if contentType == "" {
+		contentType = "image/jpeg"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	http.ServeContent(w, r, "", photo.CreatedAt, file)
 }
 
 func (s *Server) methodPost(handler http.HandlerFunc) http.HandlerFunc {
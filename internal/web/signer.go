@@ -0,0 +1,67 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrURLExpired       = errors.New("url expired")
+)
+
+// URLSigner подписывает ссылки на фото: ссылка действует ограниченное время
+// и только для ситуации, которой принадлежит фото
+type URLSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewURLSigner(secret []byte, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: secret, ttl: ttl}
+}
+
+// Sign возвращает query-параметры подписанной ссылки
+func (s *URLSigner) Sign(photoID, situationID int, now time.Time) url.Values {
+	exp := now.Add(s.ttl).Unix()
+
+	q := url.Values{}
+	q.Set("s", strconv.Itoa(situationID))
+	q.Set("exp", strconv.FormatInt(exp, 10))
+	q.Set("sig", s.signature(photoID, situationID, exp))
+	return q
+}
+
+// Verify проверяет подпись и срок действия и возвращает ID ситуации, к которой привязана ссылка
+func (s *URLSigner) Verify(photoID int, q url.Values, now time.Time) (int, error) {
+	situationID, err := strconv.Atoi(q.Get("s"))
+	if err != nil {
+		return 0, ErrInvalidSignature
+	}
+	exp, err := strconv.ParseInt(q.Get("exp"), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidSignature
+	}
+
+	expected := s.signature(photoID, situationID, exp)
+	if !hmac.Equal([]byte(expected), []byte(q.Get("sig"))) {
+		return 0, ErrInvalidSignature
+	}
+	if now.Unix() > exp {
+		return 0, ErrURLExpired
+	}
+
+	return situationID, nil
+}
+
+func (s *URLSigner) signature(photoID, situationID int, exp int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%d:%d:%d", photoID, situationID, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
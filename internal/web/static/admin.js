@@ -0,0 +1,409 @@
+// DOM Elements
+const loginScreen = document.getElementById('loginScreen');
+const listScreen = document.getElementById('listScreen');
+const editScreen = document.getElementById('editScreen');
+
+const logoutBtn = document.getElementById('logoutBtn');
+const searchInput = document.getElementById('searchInput');
+const newSituationBtn = document.getElementById('newSituationBtn');
+const situationList = document.getElementById('situationList');
+const prevPageBtn = document.getElementById('prevPageBtn');
+const nextPageBtn = document.getElementById('nextPageBtn');
+const pageInfo = document.getElementById('pageInfo');
+const resetBtn = document.getElementById('resetBtn');
+const deleteAllBtn = document.getElementById('deleteAllBtn');
+
+const backBtn = document.getElementById('backBtn');
+const editTitle = document.getElementById('editTitle');
+const answerInput = document.getElementById('answerInput');
+const aliasesInput = document.getElementById('aliasesInput');
+const hintsInput = document.getElementById('hintsInput');
+const difficultyInputs = document.getElementById('difficultyInputs');
+const decksField = document.getElementById('decksField');
+const deckInputs = document.getElementById('deckInputs');
+const usedField = document.getElementById('usedField');
+const usedInput = document.getElementById('usedInput');
+const photoList = document.getElementById('photoList');
+const photoInput = document.getElementById('photoInput');
+const saveBtn = document.getElementById('saveBtn');
+const deleteBtn = document.getElementById('deleteBtn');
+
+const snackbar = document.getElementById('snackbar');
+
+// State
+const PAGE_SIZE = 20;
+const MAX_PHOTOS = 5;
+const DIFFICULTY_LABELS = { 1: 'Лёгкая', 2: 'Средняя', 3: 'Сложная' };
+
+let page = 0;
+let total = 0;
+let searchTimer = null;
+let current = null;        // Открытая ситуация (null — создаём новую)
+let draggedPhotoId = null; // Фото, которое сейчас перетаскивают
+
+// API calls. Ответ 401 означает, что сессия истекла — показываем экран входа
+async function api(endpoint, method = 'GET', body = null) {
+    try {
+        const options = { method };
+        if (body instanceof FormData) {
+            options.body = body;
+        } else if (body) {
+            options.headers = { 'Content-Type': 'application/json' };
+            options.body = JSON.stringify(body);
+        }
+        const response = await fetch(`/api/${endpoint}`, options);
+        if (response.status === 401 || response.status === 403) {
+            showScreen(loginScreen);
+            logoutBtn.classList.add('hidden');
+            return null;
+        }
+        const data = await response.json();
+        if (!data.success && data.message) {
+            showSnackbar(data.message);
+        }
+        return data;
+    } catch (error) {
+        console.error('API Error:', error);
+        showSnackbar('Ошибка соединения с сервером');
+        return null;
+    }
+}
+
+// UI Functions
+function showScreen(screen) {
+    loginScreen.classList.add('hidden');
+    listScreen.classList.add('hidden');
+    editScreen.classList.add('hidden');
+    screen.classList.remove('hidden');
+}
+
+function showSnackbar(message, duration = 3000) {
+    snackbar.textContent = message;
+    snackbar.classList.remove('hidden');
+    setTimeout(() => {
+        snackbar.classList.add('hidden');
+    }, duration);
+}
+
+function setBusy(busy) {
+    saveBtn.disabled = busy;
+    deleteBtn.disabled = busy;
+    photoInput.disabled = busy;
+}
+
+function escapeHtml(text) {
+    const div = document.createElement('div');
+    div.textContent = text;
+    return div.innerHTML;
+}
+
+// Список ситуаций
+async function loadSituations() {
+    const q = encodeURIComponent(searchInput.value.trim());
+    const data = await api(`admin/situations?q=${q}&offset=${page * PAGE_SIZE}&limit=${PAGE_SIZE}`);
+    if (!data || !data.success) return;
+
+    total = data.total;
+    const pages = Math.max(Math.ceil(total / PAGE_SIZE), 1);
+    if (page >= pages) {
+        page = pages - 1;
+        return loadSituations();
+    }
+
+    if (data.situations.length === 0) {
+        situationList.innerHTML = '<p class="card__text">Ситуаций не найдено</p>';
+    } else {
+        situationList.innerHTML = data.situations.map(s => `
+            <div class="admin-list__item" data-id="${s.id}">
+                <span class="admin-list__status">${s.inPlay ? '▶️' : s.used ? '✔️' : '🆕'}</span>
+                <span class="admin-list__answer">#${s.id} ${escapeHtml(s.answer)}</span>
+                <span class="deck-option__count">${s.photoCount} 📷 · ${DIFFICULTY_LABELS[s.difficulty] || ''}</span>
+            </div>
+        `).join('');
+    }
+
+    pageInfo.textContent = `стр. ${page + 1} из ${pages} · всего ${total}`;
+    prevPageBtn.disabled = page === 0;
+    nextPageBtn.disabled = page >= pages - 1;
+}
+
+function openList() {
+    current = null;
+    showScreen(listScreen);
+    loadSituations();
+}
+
+// Колоды для карточки ситуации
+async function loadDecks() {
+    const decks = await api('decks');
+    if (!Array.isArray(decks) || decks.length === 0) {
+        decksField.classList.add('hidden');
+        return;
+    }
+
+    deckInputs.innerHTML = decks.map(deck => `
+        <label class="deck-option" title="${escapeHtml(deck.description || '')}">
+            <input type="checkbox" value="${deck.id}">
+            ${escapeHtml(deck.name)}
+        </label>
+    `).join('');
+    decksField.classList.remove('hidden');
+}
+
+function selectedDecks() {
+    return Array.from(deckInputs.querySelectorAll('input:checked')).map(input => Number(input.value));
+}
+
+function enteredAliases() {
+    return aliasesInput.value.split('\n').map(alias => alias.trim()).filter(alias => alias !== '');
+}
+
+function enteredHints() {
+    return hintsInput.value.split('\n').map(hint => hint.trim()).filter(hint => hint !== '');
+}
+
+function selectedDifficulty() {
+    const input = difficultyInputs.querySelector('input:checked');
+    return input ? Number(input.value) : 2;
+}
+
+// Карточка ситуации
+function openNew() {
+    current = null;
+    editTitle.textContent = 'Новая ситуация';
+    answerInput.value = '';
+    aliasesInput.value = '';
+    hintsInput.value = '';
+    difficultyInputs.querySelector('input[value="2"]').checked = true;
+    deckInputs.querySelectorAll('input').forEach(input => input.checked = false);
+    usedField.classList.add('hidden');
+    deleteBtn.classList.add('hidden');
+    photoInput.value = '';
+    photoList.innerHTML = '';
+    showScreen(editScreen);
+    answerInput.focus();
+}
+
+async function openSituation(id) {
+    const data = await api(`admin/situations/${id}`);
+    if (!data || !data.success) return;
+    showSituation(data.situation);
+    showScreen(editScreen);
+}
+
+function showSituation(situation) {
+    current = situation;
+    editTitle.textContent = `Ситуация #${situation.id}` + (situation.inPlay ? ' ▶️ сейчас в игре' : '');
+    answerInput.value = situation.answer;
+    aliasesInput.value = (situation.aliases || []).join('\n');
+    hintsInput.value = (situation.hints || []).join('\n');
+    const difficulty = difficultyInputs.querySelector(`input[value="${situation.difficulty}"]`);
+    if (difficulty) difficulty.checked = true;
+    const decks = situation.decks || [];
+    deckInputs.querySelectorAll('input').forEach(input => {
+        input.checked = decks.includes(Number(input.value));
+    });
+    usedInput.checked = situation.used;
+    usedField.classList.remove('hidden');
+    deleteBtn.classList.remove('hidden');
+    photoInput.value = '';
+    renderPhotos();
+}
+
+function renderPhotos() {
+    const photos = current ? current.photos || [] : [];
+    photoList.innerHTML = photos.map(p => `
+        <div class="admin-photo" draggable="true" data-id="${p.id}">
+            <img src="${p.url}" alt="Фото" loading="lazy">
+            <button type="button" class="btn-icon btn-remove" title="Удалить фото">✕</button>
+        </div>
+    `).join('');
+}
+
+async function save() {
+    const answer = answerInput.value.trim();
+    if (!answer) {
+        showSnackbar('Укажите ответ');
+        return;
+    }
+
+    setBusy(true);
+    try {
+        if (!current) {
+            await create(answer);
+            return;
+        }
+
+        const data = await api(`admin/situations/${current.id}/update`, 'POST', {
+            answer,
+            aliases: enteredAliases(),
+            hints: enteredHints(),
+            difficulty: selectedDifficulty(),
+            decks: selectedDecks(),
+            used: usedInput.checked,
+        });
+        if (!data || !data.success) return;
+        showSituation(data.situation);
+
+        // Новые фото загружаем отдельным запросом
+        if (photoInput.files.length > 0) {
+            await uploadPhotos();
+        } else {
+            showSnackbar(data.message);
+        }
+    } finally {
+        setBusy(false);
+    }
+}
+
+async function create(answer) {
+    if (photoInput.files.length === 0) {
+        showSnackbar('Добавьте хотя бы одно фото');
+        return;
+    }
+    if (photoInput.files.length > MAX_PHOTOS) {
+        showSnackbar(`Не больше ${MAX_PHOTOS} фото`);
+        return;
+    }
+
+    const form = new FormData();
+    form.append('answer', answer);
+    enteredAliases().forEach(alias => form.append('aliases', alias));
+    enteredHints().forEach(hint => form.append('hints', hint));
+    form.append('difficulty', selectedDifficulty());
+    selectedDecks().forEach(id => form.append('decks', id));
+    Array.from(photoInput.files).forEach(file => form.append('photos', file));
+
+    showSnackbar('⏳ Загружаю фото...');
+    const data = await api('admin/situations/create', 'POST', form);
+    if (!data || !data.success) return;
+    showSituation(data.situation);
+    showSnackbar(data.message);
+}
+
+async function uploadPhotos() {
+    const form = new FormData();
+    Array.from(photoInput.files).forEach(file => form.append('photos', file));
+
+    showSnackbar('⏳ Загружаю фото...');
+    const data = await api(`admin/situations/${current.id}/photos`, 'POST', form);
+    if (!data || !data.success) return;
+    showSituation(data.situation);
+    showSnackbar(data.message);
+}
+
+async function deletePhoto(photoId) {
+    if (!confirm('Удалить это фото?')) return;
+
+    const data = await api(`admin/photos/${photoId}/delete`, 'POST');
+    if (!data || !data.success) return;
+    showSituation(data.situation);
+}
+
+async function reorderPhotos(photoIds) {
+    const data = await api(`admin/situations/${current.id}/photos/order`, 'POST', { photoIds });
+    if (!data || !data.success) {
+        renderPhotos();
+        return;
+    }
+    showSituation(data.situation);
+}
+
+async function deleteSituation() {
+    if (!current || !confirm(`Удалить ситуацию #${current.id} вместе с фото?`)) return;
+
+    const data = await api(`admin/situations/${current.id}/delete`, 'POST');
+    if (!data || !data.success) return;
+    showSnackbar(data.message);
+    openList();
+}
+
+async function resetGame() {
+    if (!confirm('Сбросить игру? Все ситуации снова станут доступны')) return;
+
+    const data = await api('admin/reset', 'POST');
+    if (data && data.success) {
+        showSnackbar(data.message);
+        loadSituations();
+    }
+}
+
+async function deleteAll() {
+    if (!confirm('Удалить ВСЕ ситуации и фото? Это действие необратимо')) return;
+    if (prompt('Чтобы подтвердить, введите УДАЛИТЬ') !== 'УДАЛИТЬ') return;
+
+    const data = await api('admin/delete-all', 'POST');
+    if (data && data.success) {
+        showSnackbar(data.message);
+        page = 0;
+        loadSituations();
+    }
+}
+
+async function logout() {
+    await fetch('/api/admin/logout', { method: 'POST' });
+    logoutBtn.classList.add('hidden');
+    showScreen(loginScreen);
+}
+
+// Перетаскивание фото меняет порядок показа
+photoList.addEventListener('dragstart', (e) => {
+    const item = e.target.closest('.admin-photo');
+    if (item) draggedPhotoId = Number(item.dataset.id);
+});
+
+photoList.addEventListener('dragover', (e) => {
+    if (draggedPhotoId !== null) e.preventDefault();
+});
+
+photoList.addEventListener('drop', (e) => {
+    e.preventDefault();
+    const target = e.target.closest('.admin-photo');
+    if (!target || draggedPhotoId === null || !current) return;
+
+    const ids = current.photos.map(p => p.id);
+    const from = ids.indexOf(draggedPhotoId);
+    const to = ids.indexOf(Number(target.dataset.id));
+    draggedPhotoId = null;
+    if (from === -1 || to === -1 || from === to) return;
+
+    ids.splice(to, 0, ids.splice(from, 1)[0]);
+    reorderPhotos(ids);
+});
+
+photoList.addEventListener('click', (e) => {
+    const btn = e.target.closest('.btn-remove');
+    if (btn) deletePhoto(Number(btn.closest('.admin-photo').dataset.id));
+});
+
+situationList.addEventListener('click', (e) => {
+    const item = e.target.closest('.admin-list__item');
+    if (item) openSituation(Number(item.dataset.id));
+});
+
+// Initialize when DOM is ready
+document.addEventListener('DOMContentLoaded', async () => {
+    searchInput.addEventListener('input', () => {
+        clearTimeout(searchTimer);
+        searchTimer = setTimeout(() => {
+            page = 0;
+            loadSituations();
+        }, 300);
+    });
+    prevPageBtn.addEventListener('click', () => { page--; loadSituations(); });
+    nextPageBtn.addEventListener('click', () => { page++; loadSituations(); });
+    newSituationBtn.addEventListener('click', openNew);
+    backBtn.addEventListener('click', openList);
+    saveBtn.addEventListener('click', save);
+    deleteBtn.addEventListener('click', deleteSituation);
+    resetBtn.addEventListener('click', resetGame);
+    deleteAllBtn.addEventListener('click', deleteAll);
+    logoutBtn.addEventListener('click', logout);
+
+    const me = await api('admin/me');
+    if (!me || !me.success) return;
+
+    logoutBtn.classList.remove('hidden');
+    await loadDecks();
+    openList();
+});
@@ -5,13 +5,34 @@ const gameOverScreen = document.getElementById('gameOverScreen');
 
 const playersForm = document.getElementById('playersForm');
 const addPlayerBtn = document.getElementById('addPlayerBtn');
+const teamModeInput = document.getElementById('teamModeInput');
+const rotateMembersOption = document.getElementById('rotateMembersOption');
+const rotateMembersInput = document.getElementById('rotateMembersInput');
 const createSessionBtn = document.getElementById('createSessionBtn');
+const deckPicker = document.getElementById('deckPicker');
+const deckList = document.getElementById('deckList');
+const difficultyList = document.getElementById('difficultyList');
+const balancedInput = document.getElementById('balancedInput');
+const difficultyBadge = document.getElementById('difficultyBadge');
+const timerList = document.getElementById('timerList');
+const timerBadge = document.getElementById('timerBadge');
+const scoreRuleInput = document.getElementById('scoreRuleInput');
+const scoreRuleValues = document.getElementById('scoreRuleValues');
+const joinCodeInput = document.getElementById('joinCodeInput');
+const joinSessionBtn = document.getElementById('joinSessionBtn');
+const roomInfo = document.getElementById('roomInfo');
+const roomCodeSpan = document.getElementById('roomCode');
 
 const currentPlayerBanner = document.getElementById('currentPlayerBanner');
 const currentPlayerName = document.getElementById('currentPlayerName');
+const currentTeamName = document.getElementById('currentTeamName');
 
 const moreBtn = document.getElementById('moreBtn');
 const answerBtn = document.getElementById('answerBtn');
+const hintBtn = document.getElementById('hintBtn');
+const hintsCard = document.getElementById('hintsCard');
+const hintsList = document.getElementById('hintsList');
+const hintPenalty = document.getElementById('hintPenalty');
 const nextBtn = document.getElementById('nextBtn');
 const newGameBtn = document.getElementById('newGameBtn');
 
@@ -27,6 +48,7 @@ const remainingSpan = document.getElementById('remaining');
 const answerCard = document.getElementById('answerCard');
 const answerText = document.getElementById('answerText');
 const answerWaiting = document.getElementById('answerWaiting');
+const answerSuggested = document.getElementById('answerSuggested');
 
 const scoreboardCard = document.getElementById('scoreboardCard');
 const scoreboardList = document.getElementById('scoreboardList');
@@ -36,6 +58,9 @@ const snackbar = document.getElementById('snackbar');
 
 // State
 let isLoading = false;
+let sessionCode = null;    // Код комнаты текущей сессии
+let currentRoundId = 0;    // Номер раунда, который сейчас на экране
+let lastScoreboard = [];   // Последняя известная таблица очков
 let playerCount = 1;
 const MAX_PLAYERS = 10;
 
@@ -45,12 +70,16 @@ let currentPhotoIndex = 0; // Текущий индекс в карусели
 let totalPhotosCount = 1;  // Всего фото в ситуации
 let unlockedPhotos = 1;    // Сколько фото открыто
 
+// Round timer state
+let timerDeadline = 0;     // Когда таймер раунда откроет следующее фото или ответ (по часам браузера)
+let timerInterval = null;
+
 // Player inputs management
 function addPlayerInput() {
     console.log('addPlayerInput called, current count:', playerCount);
     
     if (playerCount >= MAX_PLAYERS) {
-        showSnackbar('Максимум 10 игроков');
+        showSnackbar(teamModeInput.checked ? 'Максимум 10 команд' : 'Максимум 10 игроков');
         return;
     }
     
@@ -64,6 +93,10 @@ function addPlayerInput() {
     `;
     
     playersForm.appendChild(group);
+    if (teamModeInput.checked) {
+        addMembersInput(group);
+    }
+    updatePlaceholders();
     
     // Focus new input
     group.querySelector('input').focus();
@@ -93,10 +126,51 @@ function updateRemoveButtons() {
 function updatePlaceholders() {
     const inputs = playersForm.querySelectorAll('.player-input');
     inputs.forEach((input, idx) => {
-        input.placeholder = `Игрок ${idx + 1}`;
+        input.placeholder = teamModeInput.checked ? `Команда ${idx + 1}` : `Игрок ${idx + 1}`;
     });
 }
 
+// Командная игра: у каждой строки появляется поле участников команды через запятую
+function addMembersInput(group) {
+    const members = document.createElement('input');
+    members.type = 'text';
+    members.className = 'input team-members-input';
+    members.placeholder = 'Участники через запятую';
+    members.maxLength = 220;
+    group.querySelector('.btn-remove').before(members);
+}
+
+function updateTeamMode() {
+    const teams = teamModeInput.checked;
+    playersForm.querySelectorAll('.player-input-group').forEach(group => {
+        const members = group.querySelector('.team-members-input');
+        if (teams && !members) {
+            addMembersInput(group);
+        } else if (!teams && members) {
+            members.remove();
+        }
+    });
+    rotateMembersOption.classList.toggle('hidden', !teams);
+    addPlayerBtn.textContent = teams ? '+ Добавить команду' : '+ Добавить игрока';
+    updatePlaceholders();
+}
+
+// selectedTeams возвращает команды с участниками; null — если у какой-то команды нет участников
+function selectedTeams() {
+    const teams = [];
+    for (const group of playersForm.querySelectorAll('.player-input-group')) {
+        const name = group.querySelector('.player-input').value.trim();
+        const members = group.querySelector('.team-members-input').value
+            .split(',')
+            .map(member => member.trim())
+            .filter(Boolean);
+        if (!name && members.length === 0) continue;
+        if (!name || members.length === 0) return null;
+        teams.push({ name, members });
+    }
+    return teams;
+}
+
 // API calls
 async function api(endpoint, method = 'GET', body = null) {
     try {
@@ -114,6 +188,26 @@ async function api(endpoint, method = 'GET', body = null) {
     }
 }
 
+// Путь к API текущей комнаты
+function sessionEndpoint(action) {
+    return action ? `sessions/${sessionCode}/${action}` : `sessions/${sessionCode}`;
+}
+
+function setSessionCode(code) {
+    sessionCode = code;
+
+    const url = new URL(window.location);
+    if (code) {
+        url.searchParams.set('room', code);
+        roomCodeSpan.textContent = code;
+        roomInfo.classList.remove('hidden');
+    } else {
+        url.searchParams.delete('room');
+        roomInfo.classList.add('hidden');
+    }
+    window.history.replaceState(null, '', url);
+}
+
 // UI Functions
 function showScreen(screen) {
     setupScreen.classList.add('hidden');
@@ -221,20 +315,28 @@ function updatePhoto(data) {
         totalPhotosSpan.textContent = data.totalPhotos;
     }
 
+    updateDifficulty(data);
+    updateTimer(data);
     updateCarouselNav();
 
     // Hide answer when new photo loads
     answerCard.classList.add('hidden');
 }
 
-function updateCurrentPlayer(player) {
+function updateCurrentPlayer(player, team) {
     if (player) {
         currentPlayerName.textContent = player.name;
         currentPlayerBanner.classList.remove('hidden');
     }
+    currentTeamName.textContent = team ? ` (команда ${team.name})` : '';
+    currentTeamName.classList.toggle('hidden', !team);
 }
 
 function updateScoreboard(scoreboard) {
+    if (scoreboard && scoreboard.length > 0) {
+        lastScoreboard = scoreboard;
+    }
+
     if (!scoreboard || scoreboard.length === 0) {
         scoreboardCard.classList.add('hidden');
         return;
@@ -251,7 +353,7 @@ function updateScoreboard(scoreboard) {
         return `
             <div class="scoreboard__item ${currentClass}">
                 <div class="scoreboard__position ${positionClass}">${positionIcon}</div>
-                <div class="scoreboard__name">${escapeHtml(player.name)}</div>
+                <div class="scoreboard__name">${escapeHtml(player.name)}${membersLine(player)}</div>
                 <div class="scoreboard__score">${player.score} 🤑</div>
             </div>
         `;
@@ -265,18 +367,24 @@ function updateFinalScoreboard(scoreboard) {
         const position = idx + 1;
         const positionIcon = position === 1 ? '🥇' : position === 2 ? '🥈' : position === 3 ? '🥉' : position;
         const winnerBadge = position === 1 ? '<span class="winner-badge">ПОБЕДИТЕЛЬ</span>' : '';
-        const scoreDisplay = Number.isInteger(player.score) ? player.score : player.score.toFixed(1);
+        const scoreDisplay = Number.isInteger(player.score) ? player.score : Math.round(player.score * 100) / 100;
         
         return `
             <div class="scoreboard__item">
                 <div class="scoreboard__position scoreboard__position--${position}">${positionIcon}</div>
-                <div class="scoreboard__name">${escapeHtml(player.name)}${winnerBadge}</div>
+                <div class="scoreboard__name">${escapeHtml(player.name)}${winnerBadge}${membersLine(player)}</div>
                 <div class="scoreboard__score">${scoreDisplay} 🤑</div>
             </div>
         `;
     }).join('');
 }
 
+// membersLine — участники команды под её названием в таблице очков
+function membersLine(row) {
+    if (!row.members || row.members.length === 0) return '';
+    return `<div class="scoreboard__members">${escapeHtml(row.members.join(', '))}</div>`;
+}
+
 function escapeHtml(text) {
     const div = document.createElement('div');
     div.textContent = text;
@@ -284,45 +392,202 @@ function escapeHtml(text) {
 }
 
 async function updateStats() {
-    const data = await api('stats');
-    if (data) {
+    const data = await api(sessionCode ? sessionEndpoint('stats') : 'stats');
+    if (data && data.remaining !== undefined) {
         remainingSpan.textContent = data.remaining;
     }
 }
 
+// Колоды для выбора при создании комнаты
+async function loadDecks() {
+    const decks = await api('decks');
+    if (!Array.isArray(decks) || decks.length === 0) {
+        deckPicker.classList.add('hidden');
+        return;
+    }
+
+    deckList.innerHTML = decks.map(deck => `
+        <label class="deck-option" title="${escapeHtml(deck.description || '')}">
+            <input type="checkbox" value="${deck.id}">
+            ${escapeHtml(deck.name)}
+            <span class="deck-option__count">${deck.remaining}/${deck.total}</span>
+        </label>
+    `).join('');
+    deckPicker.classList.remove('hidden');
+}
+
+function selectedDecks() {
+    return Array.from(deckList.querySelectorAll('input:checked')).map(input => Number(input.value));
+}
+
+function selectedDifficulties() {
+    return Array.from(difficultyList.querySelectorAll('input[value]:checked')).map(input => Number(input.value));
+}
+
+const DIFFICULTY_LABELS = { 1: 'Лёгкая', 2: 'Средняя', 3: 'Сложная' };
+
+function updateDifficulty(data) {
+    if (!data.difficulty) return;
+    difficultyBadge.textContent = `${DIFFICULTY_LABELS[data.difficulty]} ×${data.multiplier}`;
+    difficultyBadge.classList.remove('hidden');
+}
+
+function selectedTimer() {
+    const value = timerList.querySelector('input:checked')?.value;
+    return value ? Number(value) : null;
+}
+
+// Правило очков: BazuCoin за ответ с 1-го, 2-го, 3-го… фото; null — если выключено или введено с ошибкой
+function selectedScoreRule() {
+    if (!scoreRuleInput.checked) return [];
+
+    const values = scoreRuleValues.value.split(/[\s,;]+/).filter(Boolean).map(Number);
+    const valid = values.length > 0 && values.length <= 10 &&
+        values.every(v => v >= 0 && v <= 3 && Number.isInteger(v * 2));
+    return valid ? values : null;
+}
+
+// Подсказка судье под ответом: сколько BazuCoin даёт правило комнаты за этот ход
+function updateSuggestedScore(data) {
+    if (data.suggestedScore === undefined || data.suggestedScore === null) {
+        answerSuggested.classList.add('hidden');
+        return;
+    }
+    answerSuggested.textContent = `По правилу комнаты, если угадано: ${data.suggestedScore} 🤑`;
+    answerSuggested.classList.remove('hidden');
+}
+
+// Подсказки раунда: открытые показываются списком, кнопка видна, пока у ситуации есть неоткрытые
+function updateHints(data) {
+    const hints = data.hints || [];
+    const total = data.totalHints || 0;
+
+    hintBtn.classList.toggle('hidden', total === 0);
+    hintBtn.disabled = hints.length >= total || Boolean(data.answerShown);
+
+    hintsCard.classList.toggle('hidden', hints.length === 0);
+    hintsList.innerHTML = hints.map(hint => `<li>${escapeHtml(hint)}</li>`).join('');
+    hintPenalty.textContent = `Очки за ход: −${data.hintPenalty} 🤑`;
+}
+
+// Таймер раунда: сервер присылает, сколько осталось, а отсчёт идёт локально
+function updateTimer(data) {
+    if (!data.timeLeftMs) {
+        stopTimer();
+        return;
+    }
+
+    timerDeadline = Date.now() + data.timeLeftMs;
+    renderTimer();
+    timerBadge.classList.remove('hidden');
+    if (!timerInterval) {
+        timerInterval = setInterval(renderTimer, 250);
+    }
+}
+
+function renderTimer() {
+    const left = Math.max(0, Math.ceil((timerDeadline - Date.now()) / 1000));
+    timerBadge.textContent = `⏱ ${left} с`;
+    timerBadge.classList.toggle('timer-badge--urgent', left <= 5);
+
+    // Время вышло — сразу узнаём, что открыл сервер, не дожидаясь опроса
+    if (left === 0) {
+        stopTimer();
+        pollState();
+    }
+}
+
+function stopTimer() {
+    if (timerInterval) {
+        clearInterval(timerInterval);
+        timerInterval = null;
+    }
+    timerDeadline = 0;
+    timerBadge.classList.add('hidden');
+}
+
 // Session & Game actions
 async function createSession() {
     const inputs = playersForm.querySelectorAll('.player-input');
     const players = [];
+    let teams = [];
     
-    inputs.forEach(input => {
-        const name = input.value.trim();
-        if (name) {
-            players.push(name);
+    if (teamModeInput.checked) {
+        teams = selectedTeams();
+        if (teams === null) {
+            showSnackbar('У каждой команды должны быть название и участники');
+            return;
         }
-    });
+        if (teams.length === 0) {
+            showSnackbar('Введите хотя бы одну команду');
+            return;
+        }
+    } else {
+        inputs.forEach(input => {
+            const name = input.value.trim();
+            if (name) {
+                players.push(name);
+            }
+        });
+        
+        if (players.length === 0) {
+            showSnackbar('Введите хотя бы одного игрока');
+            return;
+        }
+    }
     
-    if (players.length === 0) {
-        showSnackbar('Введите хотя бы одного игрока');
+    const scoreRule = selectedScoreRule();
+    if (scoreRule === null) {
+        showSnackbar('Правило очков: до 10 чисел от 0 до 3 с шагом 0.5 через запятую');
         return;
     }
-    
-    const data = await api('session/create', 'POST', { players });
+
+    const data = await api('session/create', 'POST', {
+        players,
+        decks: selectedDecks(),
+        difficulties: selectedDifficulties(),
+        balanced: balancedInput.checked,
+        photoSeconds: selectedTimer(),
+        scoreRule,
+        teams,
+        rotateMembers: rotateMembersInput.checked,
+    });
     
     if (!data || !data.success) {
         showSnackbar(data?.message || 'Ошибка создания сессии');
         return;
     }
     
+    setSessionCode(data.session.code);
+
     // Start game immediately after session creation
     await startGame();
 }
 
+async function joinSession(code) {
+    code = (code || '').trim().toUpperCase();
+    if (!code) {
+        showSnackbar('Введите код комнаты');
+        return;
+    }
+
+    const data = await api(`sessions/${encodeURIComponent(code)}`);
+
+    if (!data || !data.success) {
+        showSnackbar(data?.message || 'Комната не найдена');
+        setSessionCode(null);
+        return;
+    }
+
+    setSessionCode(data.session.code);
+    await startGame();
+}
+
 async function startGame() {
     setLoading(true);
     resetPhotoCarousel();
     
-    const data = await api('start', 'POST');
+    const data = await api(sessionEndpoint('start'), 'POST');
     setLoading(false);
 
     if (!data) return;
@@ -334,9 +599,13 @@ async function startGame() {
     }
 
     if (data.success) {
+        currentRoundId = data.round || 0;
         showScreen(gameScreen);
+        // При подключении к идущему раунду сначала добавляем уже открытые фото
+        (data.photoUrls || []).slice(0, -1).forEach(url => addPhotoToCarousel(url));
         updatePhoto(data);
-        updateCurrentPlayer(data.currentPlayer);
+        updateHints(data);
+        updateCurrentPlayer(data.currentPlayer, data.currentTeam);
         updateScoreboard(data.scoreboard);
         updateStats();
     } else {
@@ -352,7 +621,7 @@ async function unlockNextPhoto() {
     }
 
     setLoading(true);
-    const data = await api('next-photo', 'POST');
+    const data = await api(sessionEndpoint('next-photo'), 'POST');
     setLoading(false);
 
     if (!data) return;
@@ -367,13 +636,15 @@ async function unlockNextPhoto() {
 async function showAnswer() {
     if (isLoading) return;
 
-    const data = await api('answer', 'POST');
+    const data = await api(sessionEndpoint('answer'), 'POST');
 
     if (!data) return;
 
     if (data.success) {
+        stopTimer();
         answerText.textContent = data.answer;
         answerCard.classList.remove('hidden');
+        updateSuggestedScore(data);
         
         // Show waiting message if scores need to be entered
         if (data.needScore) {
@@ -384,18 +655,33 @@ async function showAnswer() {
     }
 }
 
+async function showHint() {
+    if (isLoading || hintBtn.classList.contains('hidden')) return;
+
+    const data = await api(sessionEndpoint('hint'), 'POST');
+
+    if (!data) return;
+
+    if (data.success) {
+        updateHints(data);
+    } else {
+        showSnackbar(data.message || 'Подсказок больше нет');
+    }
+}
+
 async function nextRound() {
     if (isLoading) return;
 
     setLoading(true);
     resetPhotoCarousel();
     
-    const data = await api('next-round', 'POST');
+    const data = await api(sessionEndpoint('next-round'), 'POST');
     setLoading(false);
 
     if (!data) return;
 
     if (data.gameOver) {
+        stopTimer();
         updateFinalScoreboard(data.scoreboard);
         showScreen(gameOverScreen);
         updateStats();
@@ -403,8 +689,10 @@ async function nextRound() {
     }
 
     if (data.success) {
+        currentRoundId = data.round || 0;
         updatePhoto(data);
-        updateCurrentPlayer(data.currentPlayer);
+        updateHints(data);
+        updateCurrentPlayer(data.currentPlayer, data.currentTeam);
         updateScoreboard(data.scoreboard);
         answerWaiting.classList.add('hidden');
         updateStats();
@@ -422,44 +710,96 @@ function newGame() {
         </div>
     `;
     playerCount = 1;
+    updateTeamMode();
     updateRemoveButtons();
     resetPhotoCarousel();
+    setSessionCode(null);
+    loadDecks();
+    updateStats();
     
     showScreen(setupScreen);
 }
 
-// Polling for scoreboard updates (to see score changes from bot)
-let scoreboardPollInterval = null;
+// Polling for room state (to see score changes and moves made from the bot or another screen)
+let statePollInterval = null;
 
-function startScoreboardPolling() {
-    if (scoreboardPollInterval) return;
+function startStatePolling() {
+    if (statePollInterval) return;
     
-    scoreboardPollInterval = setInterval(async () => {
-        if (gameScreen.classList.contains('hidden')) {
-            stopScoreboardPolling();
-            return;
-        }
-        
-        const data = await api('scoreboard');
-        if (data && data.success) {
-            updateScoreboard(data.scoreboard);
-        }
-    }, 2000);
+    statePollInterval = setInterval(pollState, 2000);
+}
+
+async function pollState() {
+    if (gameScreen.classList.contains('hidden')) {
+        stopStatePolling();
+        return;
+    }
+
+    const data = await api(sessionEndpoint('state'));
+    if (!data) return;
+
+    if (!data.success) {
+        // Комнату завершили в другом месте (например, из Telegram)
+        stopStatePolling();
+        stopTimer();
+        updateFinalScoreboard(lastScoreboard);
+        showScreen(gameOverScreen);
+        updateStats();
+        return;
+    }
+
+    applyState(data);
 }
 
-function stopScoreboardPolling() {
-    if (scoreboardPollInterval) {
-        clearInterval(scoreboardPollInterval);
-        scoreboardPollInterval = null;
+function stopStatePolling() {
+    if (statePollInterval) {
+        clearInterval(statePollInterval);
+        statePollInterval = null;
+    }
+}
+
+function applyState(data) {
+    updateScoreboard(data.scoreboard);
+    updateCurrentPlayer(data.currentPlayer, data.currentTeam);
+
+    if (!data.round || isLoading) return;
+
+    const urls = data.photoUrls || [];
+
+    if (data.round !== currentRoundId) {
+        // Раунд сменили на другом устройстве
+        currentRoundId = data.round;
+        resetPhotoCarousel();
+        totalPhotosCount = data.totalPhotos;
+        totalPhotosSpan.textContent = data.totalPhotos;
+        urls.forEach(url => addPhotoToCarousel(url));
+        showPhotoAtIndex(photoUrls.length - 1);
+        updateDifficulty(data);
+        answerCard.classList.add('hidden');
+        answerWaiting.classList.add('hidden');
+        updateStats();
+    } else if (urls.length > unlockedPhotos) {
+        urls.slice(unlockedPhotos).forEach(url => addPhotoToCarousel(url));
+        showPhotoAtIndex(photoUrls.length - 1);
+    }
+
+    updateTimer(data);
+    updateHints(data);
+
+    if (data.answerShown && answerCard.classList.contains('hidden')) {
+        answerText.textContent = data.answer;
+        answerCard.classList.remove('hidden');
+        answerWaiting.classList.remove('hidden');
+        updateSuggestedScore(data);
     }
 }
 
 // Start polling when game screen is shown
 const observer = new MutationObserver(() => {
     if (!gameScreen.classList.contains('hidden')) {
-        startScoreboardPolling();
+        startStatePolling();
     } else {
-        stopScoreboardPolling();
+        stopStatePolling();
     }
 });
 
@@ -473,6 +813,10 @@ document.addEventListener('keydown', (e) => {
             e.preventDefault();
             createSession();
         }
+        if (e.code === 'Enter' && e.target === joinCodeInput) {
+            e.preventDefault();
+            joinSession(joinCodeInput.value);
+        }
         return;
     }
     
@@ -497,6 +841,10 @@ document.addEventListener('keydown', (e) => {
             e.preventDefault();
             showAnswer();
             break;
+        case 'KeyH':
+            e.preventDefault();
+            showHint();
+            break;
         case 'ArrowRight':
             e.preventDefault();
             if (e.shiftKey) {
@@ -522,9 +870,15 @@ document.addEventListener('DOMContentLoaded', () => {
     
     // Event listeners
     addPlayerBtn.addEventListener('click', addPlayerInput);
+    teamModeInput.addEventListener('change', updateTeamMode);
     createSessionBtn.addEventListener('click', createSession);
+    joinSessionBtn.addEventListener('click', () => joinSession(joinCodeInput.value));
+    scoreRuleInput.addEventListener('change', () => {
+        scoreRuleValues.disabled = !scoreRuleInput.checked;
+    });
     moreBtn.addEventListener('click', unlockNextPhoto);
     answerBtn.addEventListener('click', showAnswer);
+    hintBtn.addEventListener('click', showHint);
     nextBtn.addEventListener('click', nextRound);
     newGameBtn.addEventListener('click', newGame);
     
@@ -535,6 +889,13 @@ document.addEventListener('DOMContentLoaded', () => {
     // Initial setup
     updateRemoveButtons();
     updateStats();
+    loadDecks();
+
+    // Подключаемся к комнате из ссылки вида /?room=CODE
+    const room = new URLSearchParams(window.location.search).get('room');
+    if (room) {
+        joinSession(room);
+    }
     
     console.log('Setup complete');
 });
\ No newline at end of file
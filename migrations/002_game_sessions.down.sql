@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS score_entries;
+DROP TABLE IF EXISTS session_players;
+DROP TABLE IF EXISTS game_sessions;
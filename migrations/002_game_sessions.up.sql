@@ -0,0 +1,32 @@
+-- Игровые сессии веб-интерфейса
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id TEXT PRIMARY KEY,
+    current_player_id TEXT NOT NULL DEFAULT '',
+    current_round INTEGER NOT NULL DEFAULT 1,
+    is_active BOOLEAN DEFAULT TRUE,
+    is_finished BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+-- Игроки сессии
+CREATE TABLE IF NOT EXISTS session_players (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES game_sessions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    sort_order INTEGER DEFAULT 0
+);
+
+-- Начисления BazuCoin за ход
+CREATE TABLE IF NOT EXISTS score_entries (
+    id SERIAL PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES game_sessions(id) ON DELETE CASCADE,
+    player_id TEXT NOT NULL REFERENCES session_players(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_players_session_id ON session_players(session_id);
+CREATE INDEX IF NOT EXISTS idx_score_entries_session_id ON score_entries(session_id);
+CREATE INDEX IF NOT EXISTS idx_game_sessions_active ON game_sessions(is_active) WHERE is_active = TRUE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_game_sessions_active_code;
+
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS code;
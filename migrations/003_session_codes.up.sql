@@ -0,0 +1,8 @@
+-- Короткие коды комнат для параллельных сессий
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS code TEXT;
+
+UPDATE game_sessions SET code = UPPER(SUBSTRING(id FROM 1 FOR 6)) WHERE code IS NULL;
+
+ALTER TABLE game_sessions ALTER COLUMN code SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_game_sessions_active_code ON game_sessions(code) WHERE is_active = TRUE;
@@ -0,0 +1,2 @@
+ALTER TABLE photos DROP COLUMN IF EXISTS content_type;
+ALTER TABLE photos DROP COLUMN IF EXISTS storage_key;
@@ -0,0 +1,3 @@
+-- Фото, скачанные из Telegram в локальное хранилище
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS storage_key TEXT;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS content_type TEXT;
@@ -0,0 +1,3 @@
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS deck_ids;
+DROP TABLE IF EXISTS situation_decks;
+DROP TABLE IF EXISTS decks;
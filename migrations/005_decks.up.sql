@@ -0,0 +1,21 @@
+-- Тематические колоды ситуаций
+CREATE TABLE IF NOT EXISTS decks (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_decks_name ON decks(LOWER(name));
+
+-- Ситуация может входить в несколько колод
+CREATE TABLE IF NOT EXISTS situation_decks (
+    situation_id INTEGER NOT NULL REFERENCES situations(id) ON DELETE CASCADE,
+    deck_id INTEGER NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+    PRIMARY KEY (situation_id, deck_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_situation_decks_deck_id ON situation_decks(deck_id);
+
+-- Колоды, выбранные для веб-комнаты; пустой список — все ситуации
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS deck_ids INTEGER[] NOT NULL DEFAULT '{}';
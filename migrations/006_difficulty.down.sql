@@ -0,0 +1,4 @@
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS balanced;
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS difficulties;
+DROP INDEX IF EXISTS idx_situations_difficulty;
+ALTER TABLE situations DROP COLUMN IF EXISTS difficulty;
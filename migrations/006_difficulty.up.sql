@@ -0,0 +1,9 @@
+-- Сложность ситуации: 1 — лёгкая, 2 — средняя, 3 — сложная
+ALTER TABLE situations ADD COLUMN IF NOT EXISTS difficulty SMALLINT NOT NULL DEFAULT 2
+    CHECK (difficulty BETWEEN 1 AND 3);
+
+CREATE INDEX IF NOT EXISTS idx_situations_difficulty ON situations(difficulty);
+
+-- Сложности, выбранные для веб-комнаты, и режим чередования сложностей
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS difficulties INTEGER[] NOT NULL DEFAULT '{}';
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS balanced BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,10 @@
+-- Незавершённый ввод в боте (черновик /add, ожидание BazuCoin), чтобы пережить перезапуск
+CREATE TABLE IF NOT EXISTS drafts (
+    user_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    data JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drafts_updated_at ON drafts(updated_at);
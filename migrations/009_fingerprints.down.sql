@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_situations_answer_norm;
+DROP INDEX IF EXISTS idx_photos_file_unique_id;
+
+ALTER TABLE situations DROP COLUMN IF EXISTS answer_norm;
+ALTER TABLE photos DROP COLUMN IF EXISTS phash;
+ALTER TABLE photos DROP COLUMN IF EXISTS file_unique_id;
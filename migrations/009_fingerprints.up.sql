@@ -0,0 +1,11 @@
+-- Отпечатки для поиска дублей: тот же файл в Telegram, похожее изображение, тот же ответ
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS file_unique_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS phash BIGINT;
+
+-- Нормализованный ответ (как domain.NormalizeAnswer): нижний регистр, «ё» как «е», без знаков препинания
+ALTER TABLE situations ADD COLUMN IF NOT EXISTS answer_norm TEXT NOT NULL DEFAULT '';
+UPDATE situations
+SET answer_norm = btrim(regexp_replace(replace(lower(answer), 'ё', 'е'), '[^[:alnum:]]+', ' ', 'g'));
+
+CREATE INDEX IF NOT EXISTS idx_photos_file_unique_id ON photos(file_unique_id) WHERE file_unique_id <> '';
+CREATE INDEX IF NOT EXISTS idx_situations_answer_norm ON situations(answer_norm);
@@ -0,0 +1,17 @@
+-- Ситуации, предложенные игроками через /suggest и ждущие модерации
+CREATE TABLE IF NOT EXISTS submissions (
+    id SERIAL PRIMARY KEY,
+    author_id BIGINT NOT NULL,
+    author_name TEXT NOT NULL DEFAULT '',
+    answer TEXT NOT NULL,
+    difficulty SMALLINT NOT NULL DEFAULT 2 CHECK (difficulty BETWEEN 1 AND 3),
+    photo_file_ids TEXT[] NOT NULL,
+    photo_unique_ids TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reviewed_by BIGINT,
+    situation_id INTEGER REFERENCES situations(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_submissions_status ON submissions(status);
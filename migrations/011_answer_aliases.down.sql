@@ -0,0 +1 @@
+ALTER TABLE situations DROP COLUMN IF EXISTS aliases;
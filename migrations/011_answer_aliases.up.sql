@@ -0,0 +1,2 @@
+-- Синонимы ответа: другие варианты, которые засчитываются как правильные
+ALTER TABLE situations ADD COLUMN IF NOT EXISTS aliases TEXT[] NOT NULL DEFAULT '{}';
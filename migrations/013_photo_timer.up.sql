@@ -0,0 +1,3 @@
+-- Таймер веб-комнаты: через сколько секунд открывается следующее фото (0 — без таймера)
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS photo_seconds INTEGER NOT NULL DEFAULT 0
+    CHECK (photo_seconds >= 0);
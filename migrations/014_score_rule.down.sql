@@ -0,0 +1 @@
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS score_rule;
@@ -0,0 +1,2 @@
+-- Правило очков веб-комнаты: BazuCoin за ход по числу открытых фото (пусто — очки выбирает судья)
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS score_rule DOUBLE PRECISION[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS rotate_members;
+ALTER TABLE session_players DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS session_teams;
@@ -0,0 +1,14 @@
+-- Командная игра: команды веб-комнаты и их участники
+CREATE TABLE IF NOT EXISTS session_teams (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES game_sessions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    sort_order INTEGER DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_teams_session_id ON session_teams(session_id);
+
+ALTER TABLE session_players ADD COLUMN IF NOT EXISTS team_id TEXT REFERENCES session_teams(id) ON DELETE CASCADE;
+
+-- Участники команды отвечают по очереди, а не всегда капитан
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS rotate_members BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Текстовые подсказки ситуации по порядку: каждая открытая уменьшает очки за ход
+ALTER TABLE situations ADD COLUMN IF NOT EXISTS hints TEXT[] NOT NULL DEFAULT '{}';
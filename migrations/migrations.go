@@ -0,0 +1,10 @@
+// Package migrations содержит SQL-миграции схемы, встроенные в бинарник.
+//
+// Файлы называются NNN_описание.up.sql и NNN_описание.down.sql,
+// где NNN — номер версии. Применённые версии хранятся в таблице schema_migrations.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS